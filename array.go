@@ -206,7 +206,7 @@ func (arr Array) Nums() []float64 {
 
 // MarshalTo deserializes the array into another structure.
 func (arr Array) MarshalTo(v any) error {
-	return json.Unmarshal(arr.Bytes(), v)
+	return decodeInto(arr, v)
 }
 
 // SortBy sorts the array of objects by a specified parameter name.
@@ -228,6 +228,11 @@ func (arr Array) Sort(less func(a, b Value) bool) {
 	})
 }
 
+// DeepClone creates a clone of the array with all nested objects and arrays copied.
+func (arr Array) DeepClone() Array {
+	return deepCloneArray(arr)
+}
+
 // Reverse reverses the order of elements in the array.
 func (arr Array) Reverse() {
 	for i, j := 0, len(arr)-1; i < j; i, j = i+1, j-1 {
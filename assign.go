@@ -0,0 +1,526 @@
+package js
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// decodeInto stores the tree src (as built by Parse, or made of Object,
+// Array, Value and Go values) into the value pointed to by dst.
+// The result is that of json.Unmarshal(json.Marshal(src), dst), but
+// objects, arrays, numbers and strings are assigned directly by reflection.
+// Values of other types, and targets implementing json.Unmarshaler or
+// encoding.TextUnmarshaler, go through encoding/json one by one.
+func decodeInto(src, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(dst)}
+	}
+	var a assigner
+	a.assign(rv.Elem(), src)
+	return a.err
+}
+
+type assigner struct {
+	err   error        // the first error; like encoding/json, decoding goes on after it
+	path  []string     // keys of the struct fields being decoded, for errors
+	strct reflect.Type // the innermost struct being decoded, for errors
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonNumberType      = reflect.TypeFor[json.Number]()
+)
+
+var unmarshalerCache sync.Map // reflect.Type -> bool
+
+// customUnmarshaler reports whether values of type t decode themselves
+// (or are json.Number), so that they are decoded by encoding/json.
+func customUnmarshaler(t reflect.Type) bool {
+	if res, ok := unmarshalerCache.Load(t); ok {
+		return res.(bool)
+	}
+	pt := reflect.PointerTo(t)
+	res := pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) || t == jsonNumberType
+	unmarshalerCache.Store(t, res)
+	return res
+}
+
+func (a *assigner) save(err error) {
+	if a.err == nil {
+		a.err = err
+	}
+}
+
+func (a *assigner) typeError(src any, t reflect.Type) {
+	desc := jsonKind(src).String()
+	if lit, ok := numberLiteral(src); ok {
+		desc = "number " + lit
+	} else if desc == "boolean" {
+		desc = "bool"
+	}
+	te := &json.UnmarshalTypeError{Value: desc, Type: t, Field: strings.Join(a.path, ".")}
+	if a.strct != nil {
+		te.Struct = a.strct.Name()
+	}
+	a.save(te)
+}
+
+// fallback decodes src into dst through its JSON encoding.
+func (a *assigner) fallback(dst reflect.Value, src any) {
+	data, err := json.Marshal(src)
+	if err == nil {
+		err = json.Unmarshal(data, dst.Addr().Interface())
+	}
+	if err != nil {
+		if te, ok := err.(*json.UnmarshalTypeError); ok && len(a.path) > 0 {
+			te.Field = strings.TrimSuffix(strings.Join(append(a.path[:len(a.path):len(a.path)], te.Field), "."), ".")
+			if te.Struct == "" {
+				te.Struct = a.strct.Name()
+			}
+		}
+		a.save(err)
+	}
+}
+
+func (a *assigner) assign(dst reflect.Value, src any) {
+	switch s := src.(type) {
+	case Value:
+		a.assign(dst, s.val)
+		return
+	case Object:
+		src = map[string]any(s)
+	case Array:
+		src = []any(s)
+	}
+	switch s := src.(type) { // encoded as null
+	case map[string]any:
+		if s == nil {
+			src = nil
+		}
+	case []any:
+		if s == nil {
+			src = nil
+		}
+	case *OrderedObject:
+		if s == nil {
+			src = nil
+		}
+	}
+	t := dst.Type()
+	if t.Kind() == reflect.Pointer {
+		switch {
+		case src == nil:
+			dst.SetZero()
+		case t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType):
+			a.fallback(dst, src)
+		default:
+			if dst.IsNil() {
+				dst.Set(reflect.New(t.Elem()))
+			}
+			a.assign(dst.Elem(), src)
+		}
+		return
+	}
+	if customUnmarshaler(t) {
+		a.fallback(dst, src)
+		return
+	}
+	if src == nil {
+		switch t.Kind() {
+		case reflect.Map, reflect.Slice:
+			dst.SetZero()
+		case reflect.Interface:
+			if !dst.IsNil() && dst.Elem().Kind() == reflect.Pointer {
+				a.fallback(dst, src)
+			} else {
+				dst.SetZero()
+			}
+		}
+		return
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() == 0 && dst.IsNil() {
+			if v, ok := plainValue(src); ok {
+				if v != nil {
+					dst.Set(reflect.ValueOf(v))
+				}
+				return
+			}
+		}
+	case reflect.Bool:
+		if b, ok := src.(bool); ok {
+			dst.SetBool(b)
+			return
+		}
+		if isPlain(src) {
+			a.typeError(src, t)
+			return
+		}
+	case reflect.String:
+		if str, ok := src.(string); ok {
+			dst.SetString(fixUTF8(str))
+			return
+		}
+		if isPlain(src) {
+			a.typeError(src, t)
+			return
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f, ok := src.(float64); ok && f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+			if n := int64(f); !dst.OverflowInt(n) {
+				dst.SetInt(n)
+				return
+			}
+		}
+		if lit, ok := numberLiteral(src); ok {
+			if n, err := strconv.ParseInt(lit, 10, 64); err == nil && !dst.OverflowInt(n) {
+				dst.SetInt(n)
+			} else {
+				a.typeError(src, t)
+			}
+			return
+		}
+		if isPlain(src) {
+			a.typeError(src, t)
+			return
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f, ok := src.(float64); ok && f == math.Trunc(f) && f >= 0 && f <= 1<<53 {
+			if n := uint64(f); !dst.OverflowUint(n) {
+				dst.SetUint(n)
+				return
+			}
+		}
+		if lit, ok := numberLiteral(src); ok {
+			if n, err := strconv.ParseUint(lit, 10, 64); err == nil && !dst.OverflowUint(n) {
+				dst.SetUint(n)
+			} else {
+				a.typeError(src, t)
+			}
+			return
+		}
+		if isPlain(src) {
+			a.typeError(src, t)
+			return
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := src.(float64); ok && t.Kind() == reflect.Float64 && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			dst.SetFloat(f)
+			return
+		}
+		if lit, ok := numberLiteral(src); ok {
+			if f, err := strconv.ParseFloat(lit, t.Bits()); err == nil && !dst.OverflowFloat(f) {
+				dst.SetFloat(f)
+			} else {
+				a.typeError(src, t)
+			}
+			return
+		}
+		if isPlain(src) {
+			a.typeError(src, t)
+			return
+		}
+	case reflect.Struct:
+		if a.assignStruct(dst, src) {
+			return
+		}
+	case reflect.Map:
+		if a.assignMap(dst, src) {
+			return
+		}
+	case reflect.Slice, reflect.Array:
+		if a.assignArray(dst, src) {
+			return
+		}
+	}
+	a.fallback(dst, src)
+}
+
+// isPlain reports whether v is of a type that the tree of Parse is made of.
+func isPlain(v any) bool {
+	switch v.(type) {
+	case nil, bool, string, float64, json.Number, map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+func (a *assigner) assignStruct(dst reflect.Value, src any) bool {
+	sf := cachedFields(dst.Type())
+	if sf.quoted {
+		return false
+	}
+	var pairs []structPair
+	switch s := src.(type) {
+	case map[string]any:
+		pairs = make([]structPair, 0, len(s))
+		for k, v := range s {
+			if f := sf.lookup(k); f != nil {
+				pairs = append(pairs, structPair{k, v, f})
+			}
+		}
+		// a field matched by several keys ("id", "ID") takes the value
+		// of the last of them, in the key order of the JSON encoding
+		slices.SortFunc(pairs, func(x, y structPair) int { return strings.Compare(x.key, y.key) })
+	case *OrderedObject:
+		pairs = make([]structPair, 0, s.Len())
+		for k, v := range s.All() {
+			if f := sf.lookup(k); f != nil {
+				pairs = append(pairs, structPair{k, v.val, f})
+			}
+		}
+	case bool, string, float64, json.Number, []any:
+		a.typeError(src, dst.Type())
+		return true
+	default:
+		return false
+	}
+	defer func(t reflect.Type) { a.strct = t }(a.strct)
+	a.strct = dst.Type()
+	for _, p := range pairs {
+		fv, ok := fieldByIndex(dst, p.field.index)
+		if !ok {
+			a.save(fmt.Errorf("json: cannot set embedded pointer to unexported struct: %v", fv.Type().Elem()))
+			continue
+		}
+		a.path = append(a.path, p.field.name)
+		a.assign(fv, p.val)
+		a.path = a.path[:len(a.path)-1]
+	}
+	return true
+}
+
+type structPair struct {
+	key   string
+	val   any
+	field *structField
+}
+
+// fieldByIndex returns the field of v at index, allocating nil embedded
+// pointers. It fails at an unexported embedded pointer that is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return v, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func (a *assigner) assignMap(dst reflect.Value, src any) bool {
+	t := dst.Type()
+	kt := t.Key()
+	if kt.Kind() != reflect.String || reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+		return false
+	}
+	var obj map[string]any
+	switch s := src.(type) {
+	case map[string]any:
+		obj = s
+	case *OrderedObject:
+		obj = s.vals
+	case bool, string, float64, json.Number, []any:
+		a.typeError(src, t)
+		return true
+	default:
+		return false
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(t, len(obj)))
+	}
+	for k, v := range obj {
+		elem := reflect.New(t.Elem()).Elem()
+		a.assign(elem, v)
+		key := reflect.ValueOf(fixUTF8(k))
+		if kt != key.Type() {
+			key = key.Convert(kt)
+		}
+		dst.SetMapIndex(key, elem)
+	}
+	return true
+}
+
+func (a *assigner) assignArray(dst reflect.Value, src any) bool {
+	arr, ok := src.([]any)
+	if !ok {
+		if _, s := src.(string); s && dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			return false // base64
+		}
+		if isPlain(src) {
+			a.typeError(src, dst.Type())
+			return true
+		}
+		return false
+	}
+	if dst.Kind() == reflect.Array {
+		for i := 0; i < dst.Len(); i++ {
+			if i < len(arr) {
+				a.assign(dst.Index(i), arr[i])
+			} else {
+				dst.Index(i).SetZero()
+			}
+		}
+		return true
+	}
+	// like encoding/json, reuse the elements of the slice
+	if len(arr) == 0 {
+		dst.Set(reflect.MakeSlice(dst.Type(), 0, 0))
+		return true
+	}
+	if dst.Cap() < len(arr) {
+		dst.Grow(len(arr) - dst.Len())
+	}
+	dst.SetLen(len(arr))
+	for i, v := range arr {
+		a.assign(dst.Index(i), v)
+	}
+	return true
+}
+
+// plainValue converts src to the tree json.Unmarshal would decode
+// from its JSON into an `any`. It fails for values of other Go types.
+func plainValue(src any) (any, bool) {
+	switch s := src.(type) {
+	case nil, bool:
+		return s, true
+	case string:
+		return fixUTF8(s), true
+	case float64:
+		return s, !math.IsNaN(s) && !math.IsInf(s, 0)
+	case Value:
+		return plainValue(s.val)
+	case map[string]any:
+		return plainObject(s)
+	case Object:
+		return plainObject(s)
+	case *OrderedObject:
+		if s == nil {
+			return nil, true
+		}
+		return plainObject(s.vals)
+	case []any:
+		return plainArray(s)
+	case Array:
+		return plainArray(s)
+	}
+	if lit, ok := numberLiteral(src); ok {
+		f, err := strconv.ParseFloat(lit, 64)
+		return f, err == nil
+	}
+	return nil, false
+}
+
+func plainObject(obj map[string]any) (any, bool) {
+	if obj == nil {
+		return nil, true
+	}
+	res := make(map[string]any, len(obj))
+	for k, v := range obj {
+		pv, ok := plainValue(v)
+		if !ok {
+			return nil, false
+		}
+		res[fixUTF8(k)] = pv
+	}
+	return res, true
+}
+
+func plainArray(arr []any) (any, bool) {
+	if arr == nil {
+		return nil, true
+	}
+	res := make([]any, len(arr))
+	for i, v := range arr {
+		pv, ok := plainValue(v)
+		if !ok {
+			return nil, false
+		}
+		res[i] = pv
+	}
+	return res, true
+}
+
+// numberLiteral returns the JSON encoding of a number of a built-in type
+// (as produced by encoding/json) or a valid json.Number.
+func numberLiteral(v any) (string, bool) {
+	switch n := v.(type) {
+	case float64:
+		return formatFloat(n, 64)
+	case float32:
+		return formatFloat(float64(n), 32)
+	case json.Number:
+		return string(n), jsonNumberRx.MatchString(string(n))
+	case int:
+		return strconv.FormatInt(int64(n), 10), true
+	case int8:
+		return strconv.FormatInt(int64(n), 10), true
+	case int16:
+		return strconv.FormatInt(int64(n), 10), true
+	case int32:
+		return strconv.FormatInt(int64(n), 10), true
+	case int64:
+		return strconv.FormatInt(n, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(n), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(n), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(n), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(n), 10), true
+	case uint64:
+		return strconv.FormatUint(n, 10), true
+	}
+	return "", false
+}
+
+// formatFloat formats f as encoding/json does; it fails for NaN and infinities.
+func formatFloat(f float64, bits int) (string, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b := strconv.AppendFloat(nil, f, format, -1, bits)
+	if n := len(b); format == 'e' && n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+		b[n-2] = b[n-1] // e-09 => e-9
+		b = b[:n-1]
+	}
+	return string(b), true
+}
+
+// fixUTF8 replaces each byte of invalid UTF-8 in s with U+FFFD, as encoding/json does.
+func fixUTF8(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		sb.WriteRune(r)
+		i += n
+	}
+	return sb.String()
+}
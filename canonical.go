@@ -0,0 +1,209 @@
+package js
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonical returns the canonical JSON encoding of v according to RFC 8785
+// (JSON Canonicalization Scheme): object keys sorted by their UTF-16 code units,
+// numbers formatted as in ECMAScript, strings with minimal escaping, no whitespace.
+// Equal values (whatever the key order or number formatting of their source)
+// have the same canonical form, suitable for signing and hashing.
+// All numbers are IEEE 754 doubles, so integers beyond 2^53 are rounded.
+// It panics if v contains NaN, infinities or values that cannot be encoded.
+func Canonical(v any) []byte {
+	tree := must(encodeTree(v))
+	return must(appendCanonical(nil, tree))
+}
+
+// Hash writes the canonical JSON encoding (see Canonical) of the value to h
+// and returns the resulting checksum.
+func (v Value) Hash(h hash.Hash) []byte {
+	h.Write(Canonical(v.val))
+	return h.Sum(nil)
+}
+
+// SHA256 returns the SHA-256 checksum of the canonical JSON encoding of the value.
+func (v Value) SHA256() [sha256.Size]byte {
+	return sha256.Sum256(Canonical(v.val))
+}
+
+func appendCanonical(b []byte, v any) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case bool:
+		return strconv.AppendBool(b, val), nil
+	case string:
+		return appendCanonicalString(b, val), nil
+	case time.Time:
+		return appendCanonicalString(b, val.Format(time.RFC3339Nano)), nil
+	case []byte:
+		return appendCanonicalString(b, base64.StdEncoding.EncodeToString(val)), nil
+	case float64:
+		return appendES6Number(b, val)
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, compareUTF16)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendCanonicalString(b, k)
+			b = append(b, ':')
+			var err error
+			if b, err = appendCanonical(b, val[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, '}'), nil
+	case []any:
+		b = append(b, '[')
+		for i, el := range val {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = appendCanonical(b, el); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	}
+	if lit, ok := numberLiteral(v); ok {
+		f, err := strconv.ParseFloat(lit, 64)
+		if err != nil {
+			return nil, fmt.Errorf("js: number %s: %w", lit, ErrOverflow)
+		}
+		return appendES6Number(b, f)
+	}
+	if n, ok := v.(json.Number); ok {
+		return nil, fmt.Errorf("js: invalid number %q", string(n))
+	}
+	return nil, fmt.Errorf("js: cannot canonicalize %T", v)
+}
+
+// appendCanonicalString escapes only `"`, `\` and control characters;
+// invalid UTF-8 is replaced with U+FFFD.
+func appendCanonicalString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, n := utf8.DecodeRuneInString(s[i:])
+			b = utf8.AppendRune(b, r)
+			i += n
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			b = append(b, '\\', c)
+		case '\b':
+			b = append(b, '\\', 'b')
+		case '\f':
+			b = append(b, '\\', 'f')
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		case '\t':
+			b = append(b, '\\', 't')
+		default:
+			if c < ' ' {
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			} else {
+				b = append(b, c)
+			}
+		}
+		i++
+	}
+	return append(b, '"')
+}
+
+// appendES6Number formats f as Number.prototype.toString of ECMAScript does.
+func appendES6Number(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, 64)}
+	}
+	if f == 0 { // including -0
+		return append(b, '0'), nil
+	}
+	if f < 0 {
+		b = append(b, '-')
+		f = -f
+	}
+	// the shortest digits that round-trip, and the decimal exponent
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	k, n := len(digits), e+1 // the value is 0.digits * 10^n
+	switch {
+	case k <= n && n <= 21:
+		b = append(b, digits...)
+		for i := k; i < n; i++ {
+			b = append(b, '0')
+		}
+	case 0 < n && n <= 21:
+		b = append(append(append(b, digits[:n]...), '.'), digits[n:]...)
+	case -6 < n && n <= 0:
+		b = append(b, "0."...)
+		for i := n; i < 0; i++ {
+			b = append(b, '0')
+		}
+		b = append(b, digits...)
+	default:
+		b = append(b, digits[0])
+		if k > 1 {
+			b = append(append(b, '.'), digits[1:]...)
+		}
+		b = append(b, 'e')
+		if n-1 >= 0 {
+			b = append(b, '+')
+		}
+		b = strconv.AppendInt(b, int64(n-1), 10)
+	}
+	return b, nil
+}
+
+// compareUTF16 orders strings by their UTF-16 code units, as required by RFC 8785.
+func compareUTF16(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			ha, la := utf16Units(ra)
+			hb, lb := utf16Units(rb)
+			if ha != hb {
+				return cmp.Compare(ha, hb)
+			}
+			return cmp.Compare(la, lb)
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// utf16Units returns the UTF-16 encoding of r: a surrogate pair or r itself and 0.
+func utf16Units(r rune) (rune, rune) {
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		return r1, r2
+	}
+	return r, 0
+}
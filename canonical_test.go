@@ -0,0 +1,82 @@
+package js
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCanonical(t *testing.T) {
+	// RFC 8785, section 3.2.2
+	v := MustParse([]byte(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`))
+	exp := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+	require(t, string(Canonical(v)) == exp)
+
+	// RFC 8785, section 3.2.3: sorting by UTF-16 code units
+	obj := Object{"€": "Euro Sign", "\r": "Carriage Return", "דּ": "Hebrew Letter Dalet With Dagesh",
+		"1": "One", "\U0001F600": "Emoji: Grinning Face", "\u0080": "Control", "ö": "Latin Small Letter O With Diaeresis"}
+	exp = `{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control","ö":"Latin Small Letter O With Diaeresis",` +
+		`"€":"Euro Sign","😀":"Emoji: Grinning Face","` + "\ufb33" + `":"Hebrew Letter Dalet With Dagesh"}`
+	require(t, string(Canonical(obj)) == exp)
+
+	// key order, number formatting and Go types do not matter
+	a := Canonical(MustParse([]byte(`{"b":[1.0,"<&>"],"a":{"y":1e2,"x":null}}`), ParseOptions{UseNumber: true}))
+	b := Canonical(struct {
+		A map[string]any `json:"a"`
+		B []any          `json:"b"`
+	}{map[string]any{"x": nil, "y": int64(100)}, []any{uint8(1), "<&>"}})
+	require(t, string(a) == `{"a":{"x":null,"y":100},"b":[1,"<&>"]}` && string(a) == string(b))
+
+	require(t, string(Canonical(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))) == `"2024-01-02T00:00:00Z"`)
+	require(t, string(Canonical([]byte("hi"))) == `"aGk="`)
+	require(t, string(Canonical(" \x7f\xff")) == "\" \x7f�\"")
+	require(t, call(func() { Canonical(math.Inf(1)) }) != nil)
+	require(t, call(func() { Canonical(Array{make(chan int)}) }) != nil)
+}
+
+func TestCanonical_Numbers(t *testing.T) {
+	// RFC 8785, appendix B
+	for bits, exp := range map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	} {
+		require(t, string(Canonical(math.Float64frombits(bits))) == exp)
+	}
+}
+
+func TestValue_Hash(t *testing.T) {
+	a := MustParse([]byte(`{"id": 1, "tags": ["x"]}`))
+	b := NewValue(Object{"tags": Array{"x"}, "id": 1.0})
+	require(t, a.SHA256() == b.SHA256())
+	sum := sha256.Sum256([]byte(`{"id":1,"tags":["x"]}`))
+	require(t, a.SHA256() == sum && hex.EncodeToString(a.Hash(sha256.New())) == hex.EncodeToString(sum[:]))
+	require(t, a.SHA256() != NewValue(Object{"id": 2}).SHA256())
+}
@@ -0,0 +1,211 @@
+package js
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOverflow  = errors.New("value out of range")
+	ErrTruncated = errors.New("fractional part would be lost")
+)
+
+// ConversionError reports a value that cannot be converted to the requested type
+// by one of the strict conversions (Value.ToInt64, ToFloat64, etc.).
+type ConversionError struct {
+	Value any    // original value
+	Kind  Kind   // kind of the value
+	To    string // requested Go type
+	Err   error  // cause, e.g. ErrOverflow or ErrTruncated; may be nil
+}
+
+func (e *ConversionError) Error() string {
+	s := fmt.Sprintf("js: cannot convert %s %s to %s", e.Kind, excerpt(NewValue(e.Value).JSON()), e.To)
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+func (v Value) convErr(to string, err error) error {
+	return &ConversionError{v.val, v.Kind(), to, err}
+}
+
+func excerpt(s string) string {
+	if len(s) > 40 {
+		return s[:37] + "..."
+	}
+	return s
+}
+
+// ToInt64 converts a number or a numeric string to int64. Unlike Int64, it fails
+// on other types, on values that overflow int64 and on fractional numbers.
+func (v Value) ToInt64() (int64, error) {
+	switch val := v.val.(type) {
+	case int:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case int16:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint8:
+		return int64(val), nil
+	case uint16:
+		return int64(val), nil
+	case uint32:
+		return int64(val), nil
+	}
+	n, err := v.toBigInt("int64")
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() {
+		return 0, v.convErr("int64", ErrOverflow)
+	}
+	return n.Int64(), nil
+}
+
+// ToUint64 converts a number or a numeric string to uint64. It fails on other
+// types, on negative or too large values and on fractional numbers.
+func (v Value) ToUint64() (uint64, error) {
+	switch val := v.val.(type) {
+	case uint:
+		return uint64(val), nil
+	case uint8:
+		return uint64(val), nil
+	case uint16:
+		return uint64(val), nil
+	case uint32:
+		return uint64(val), nil
+	case uint64:
+		return val, nil
+	}
+	n, err := v.toBigInt("uint64")
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, v.convErr("uint64", ErrOverflow)
+	}
+	return n.Uint64(), nil
+}
+
+// toBigInt converts an integral number or numeric string to *big.Int.
+func (v Value) toBigInt(to string) (*big.Int, error) {
+	var r *big.Rat
+	switch val := v.val.(type) {
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil, v.convErr(to, ErrOverflow)
+		}
+		if val != math.Trunc(val) {
+			return nil, v.convErr(to, ErrTruncated)
+		}
+		n, _ := big.NewFloat(val).Int(nil)
+		return n, nil
+	case float32:
+		return Value{float64(val)}.toBigInt(to)
+	case string, json.Number:
+		r = parseRat(ToStr(val))
+	default:
+		if IsNum(val) {
+			r = toRat(val)
+		}
+	}
+	if r == nil {
+		return nil, v.convErr(to, nil)
+	}
+	if !r.IsInt() {
+		return nil, v.convErr(to, ErrTruncated)
+	}
+	return r.Num(), nil
+}
+
+// ToFloat64 converts a number or a numeric string to float64.
+// Unlike Float64, it fails on other types and on values out of the float64 range.
+func (v Value) ToFloat64() (float64, error) {
+	switch val := v.val.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case string, json.Number:
+		s := ToStr(val)
+		if !jsonNumberRx.MatchString(s) {
+			return 0, v.convErr("float64", nil)
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, v.convErr("float64", ErrOverflow)
+		}
+		return f, nil
+	}
+	if IsNum(v.val) {
+		return v.Float64(), nil
+	}
+	return 0, v.convErr("float64", nil)
+}
+
+// ToBool converts a boolean, or a string accepted by strconv.ParseBool
+// ("true", "false", "1", "0", etc.), to bool. Unlike Bool, it fails on other values.
+func (v Value) ToBool() (bool, error) {
+	switch val := v.val.(type) {
+	case bool:
+		return val, nil
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(val)); err == nil {
+			return b, nil
+		}
+	}
+	return false, v.convErr("bool", nil)
+}
+
+// ToTime converts a time.Time, a date string (see ParseTime) or
+// a Unix timestamp in seconds to time.Time. Unlike Time, it fails on
+// empty strings and other values.
+func (v Value) ToTime() (time.Time, error) {
+	switch val := v.val.(type) {
+	case time.Time:
+		return val, nil
+	case string:
+		if strings.TrimSpace(val) == "" {
+			return time.Time{}, v.convErr("time.Time", nil)
+		}
+		t, err := ParseTime(val)
+		if err != nil {
+			return time.Time{}, v.convErr("time.Time", err)
+		}
+		return t, nil
+	}
+	if IsNum(v.val) {
+		sec, err := v.ToInt64()
+		if err != nil {
+			return time.Time{}, v.convErr("time.Time", errors.Unwrap(err))
+		}
+		return time.Unix(sec, 0), nil
+	}
+	return time.Time{}, v.convErr("time.Time", nil)
+}
+
+// ToStringStrict returns the string value. Unlike String, it fails on
+// values that are not strings (numbers, null, etc.).
+func (v Value) ToStringStrict() (string, error) {
+	if s, ok := v.val.(string); ok {
+		return s, nil
+	}
+	return "", v.convErr("string", nil)
+}
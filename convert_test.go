@@ -0,0 +1,99 @@
+package js
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValue_ToInt64(t *testing.T) {
+	v := MustParse([]byte(`{"n":42,"s":"-7","f":2.5,"e":1e3,"big":1e19,"abc":"abc","null":null,"b":true}`))
+
+	n, err := v.Path("n").ToInt64()
+	require(t, err == nil && n == 42)
+	n, err = v.Path("s").ToInt64()
+	require(t, err == nil && n == -7)
+	n, err = v.Path("e").ToInt64()
+	require(t, err == nil && n == 1000)
+
+	_, err = v.Path("f").ToInt64()
+	require(t, errors.Is(err, ErrTruncated))
+	_, err = v.Path("big").ToInt64()
+	require(t, errors.Is(err, ErrOverflow))
+	_, err = NewValue(uint64(math.MaxUint64)).ToInt64()
+	require(t, errors.Is(err, ErrOverflow))
+	_, err = NewValue(math.NaN()).ToInt64()
+	require(t, errors.Is(err, ErrOverflow))
+
+	_, err = v.Path("abc").ToInt64()
+	var ce *ConversionError
+	require(t, errors.As(err, &ce) && ce.Kind == KindString && ce.Value == "abc" && ce.To == "int64")
+	require(t, strings.Contains(err.Error(), `"abc"`))
+	_, err = v.Path("missing").ToInt64()
+	require(t, errors.As(err, &ce) && ce.Kind == KindNull)
+	_, err = v.Path("b").ToInt64()
+	require(t, errors.As(err, &ce) && ce.Kind == KindBool)
+}
+
+func TestValue_ToUint64(t *testing.T) {
+	n, err := MustParse([]byte(`18446744073709551615`), ParseOptions{UseNumber: true}).ToUint64()
+	require(t, err == nil && n == math.MaxUint64)
+	n, err = NewValue(int64(5)).ToUint64()
+	require(t, err == nil && n == 5)
+
+	_, err = NewValue(-1).ToUint64()
+	require(t, errors.Is(err, ErrOverflow))
+	_, err = NewValue("18446744073709551616").ToUint64()
+	require(t, errors.Is(err, ErrOverflow))
+}
+
+func TestValue_ToFloat64(t *testing.T) {
+	f, err := NewValue("1.5").ToFloat64()
+	require(t, err == nil && f == 1.5)
+	f, err = NewValue(3).ToFloat64()
+	require(t, err == nil && f == 3)
+
+	_, err = NewValue("1e999").ToFloat64()
+	require(t, errors.Is(err, ErrOverflow))
+	_, err = NewValue("abc").ToFloat64()
+	require(t, err != nil)
+	_, err = NewValue(nil).ToFloat64()
+	require(t, err != nil)
+}
+
+func TestValue_ToBool(t *testing.T) {
+	b, err := NewValue(true).ToBool()
+	require(t, err == nil && b)
+	b, err = NewValue("false").ToBool()
+	require(t, err == nil && !b)
+
+	_, err = NewValue(1).ToBool()
+	require(t, err != nil)
+	_, err = NewValue("yes").ToBool()
+	require(t, err != nil)
+}
+
+func TestValue_ToTime(t *testing.T) {
+	tm, err := NewValue("2024-01-02").ToTime()
+	require(t, err == nil && tm.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	tm, err = NewValue(1700000000).ToTime()
+	require(t, err == nil && tm.Unix() == 1700000000)
+
+	_, err = NewValue("").ToTime()
+	require(t, err != nil)
+	_, err = NewValue("not a date").ToTime()
+	require(t, err != nil)
+	_, err = NewValue(1.5).ToTime()
+	require(t, errors.Is(err, ErrTruncated))
+}
+
+func TestValue_ToStringStrict(t *testing.T) {
+	s, err := NewValue("x").ToStringStrict()
+	require(t, err == nil && s == "x")
+
+	_, err = NewValue(1).ToStringStrict()
+	var ce *ConversionError
+	require(t, errors.As(err, &ce) && ce.Kind == KindNumber && ce.To == "string")
+}
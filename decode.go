@@ -0,0 +1,386 @@
+package js
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// decoder is a JSON scanner that builds a tree of map[string]any, []any,
+// float64 (or json.Number), string, bool and nil directly from the input,
+// as json.Unmarshal into an `any` does, without the reflection overhead.
+type decoder struct {
+	data      []byte
+	pos       int
+	depth     int
+	useNumber bool
+	keys      map[string]string // interned object keys
+	buf       []byte            // scratch buffer for unescaping strings
+}
+
+const (
+	maxDecodeDepth = 10000 // as encoding/json
+	maxInternKeys  = 1024
+	maxInternLen   = 64
+)
+
+var decoderPool = sync.Pool{
+	New: func() any { return &decoder{keys: map[string]string{}} },
+}
+
+// decodeJSON decodes a single JSON document, reporting errors as *SyntaxError
+// or, for numbers out of the float64 range, *json.UnmarshalTypeError.
+func decodeJSON(data []byte, useNumber bool) (v any, err error) {
+	d := decoderPool.Get().(*decoder)
+	d.data, d.pos, d.depth, d.useNumber = data, 0, 0, useNumber
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(error)
+			if !ok {
+				panic(r)
+			}
+			v, err = nil, e
+		}
+		d.data = nil
+		if len(d.keys) > maxInternKeys {
+			clear(d.keys)
+		}
+		decoderPool.Put(d)
+	}()
+	v = d.value()
+	if d.skip(); d.pos < len(d.data) {
+		d.fail("after top-level value")
+	}
+	return v, nil
+}
+
+// fail panics with a *SyntaxError for the character at the current position.
+func (d *decoder) fail(context string) {
+	if d.pos >= len(d.data) {
+		panic(newSyntaxError(d.data, int64(len(d.data)), "unexpected end of JSON input"))
+	}
+	panic(newSyntaxError(d.data, int64(d.pos), "invalid character %s %s", quoteChar(d.data[d.pos]), context))
+}
+
+// quoteChar formats c as encoding/json does in error messages.
+func quoteChar(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c == '"' {
+		return `'"'`
+	}
+	s := strconv.Quote(string(c))
+	return "'" + s[1:len(s)-1] + "'"
+}
+
+func (d *decoder) skip() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *decoder) literal(lit string, v any) any {
+	end := d.pos + len(lit)
+	for i := 1; i < len(lit); i++ {
+		if d.pos++; d.pos >= len(d.data) || d.data[d.pos] != lit[i] {
+			d.fail("in literal " + lit + " (expecting " + quoteChar(lit[i]) + ")")
+		}
+	}
+	d.pos = end
+	return v
+}
+
+func (d *decoder) value() any {
+	d.skip()
+	if d.pos >= len(d.data) {
+		d.fail("")
+	}
+	switch c := d.data[d.pos]; {
+	case c == '{':
+		return d.object()
+	case c == '[':
+		return d.array()
+	case c == '"':
+		return d.string()
+	case c == 't':
+		return d.literal("true", true)
+	case c == 'f':
+		return d.literal("false", false)
+	case c == 'n':
+		return d.literal("null", nil)
+	case c == '-' || c >= '0' && c <= '9':
+		return d.number()
+	}
+	d.fail("looking for beginning of value")
+	return nil
+}
+
+func (d *decoder) enter() {
+	if d.depth++; d.depth > maxDecodeDepth {
+		panic(newSyntaxError(d.data, int64(d.pos), "exceeded max depth"))
+	}
+	d.pos++
+}
+
+func (d *decoder) object() any {
+	d.enter()
+	obj := map[string]any{}
+	if d.skip(); d.pos < len(d.data) && d.data[d.pos] == '}' {
+		d.pos++
+		d.depth--
+		return obj
+	}
+	for {
+		if d.skip(); d.pos >= len(d.data) || d.data[d.pos] != '"' {
+			d.fail("looking for beginning of object key string")
+		}
+		key := d.key()
+		if d.skip(); d.pos >= len(d.data) || d.data[d.pos] != ':' {
+			d.fail("after object key")
+		}
+		d.pos++
+		obj[key] = d.value()
+		if d.skip(); d.pos < len(d.data) && d.data[d.pos] == ',' {
+			d.pos++
+			continue
+		}
+		if d.pos < len(d.data) && d.data[d.pos] == '}' {
+			d.pos++
+			d.depth--
+			return obj
+		}
+		d.fail("after object key:value pair")
+	}
+}
+
+func (d *decoder) array() any {
+	d.enter()
+	arr := []any{}
+	if d.skip(); d.pos < len(d.data) && d.data[d.pos] == ']' {
+		d.pos++
+		d.depth--
+		return arr
+	}
+	for {
+		arr = append(arr, d.value())
+		if d.skip(); d.pos < len(d.data) && d.data[d.pos] == ',' {
+			d.pos++
+			continue
+		}
+		if d.pos < len(d.data) && d.data[d.pos] == ']' {
+			d.pos++
+			d.depth--
+			return arr
+		}
+		d.fail("after array element")
+	}
+}
+
+// key decodes an object key, interning short keys that do not need unescaping.
+func (d *decoder) key() string {
+	start := d.pos + 1
+	raw, ok := d.rawString()
+	if !ok || len(raw) > maxInternLen {
+		return d.unquote(start, raw, ok)
+	}
+	if s, ok := d.keys[string(raw)]; ok {
+		return s
+	}
+	s := string(raw)
+	if len(d.keys) < maxInternKeys {
+		d.keys[s] = s
+	}
+	return s
+}
+
+func (d *decoder) string() string {
+	start := d.pos + 1
+	raw, ok := d.rawString()
+	if ok {
+		return string(raw)
+	}
+	return d.unquote(start, raw, ok)
+}
+
+// rawString scans a string literal; ok reports whether it is plain ASCII
+// or valid UTF-8 without escapes, so that raw is its value.
+func (d *decoder) rawString() (raw []byte, ok bool) {
+	start := d.pos + 1
+	ok = true
+	for i := start; i < len(d.data); i++ {
+		switch c := d.data[i]; {
+		case c == '"':
+			d.pos = i + 1
+			raw = d.data[start:i]
+			if ok || utf8.Valid(raw) {
+				return raw, !d.hasEscape(raw)
+			}
+			return raw, false
+		case c == '\\':
+			i++
+			if i >= len(d.data) {
+				d.pos = i
+				d.fail("")
+			}
+			switch d.data[i] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				for j := 1; j <= 4; j++ {
+					if i+j >= len(d.data) {
+						d.pos = i + j
+						d.fail("")
+					}
+					if !isHex(d.data[i+j]) {
+						d.pos = i + j
+						d.fail("in \\u hexadecimal character escape")
+					}
+				}
+				i += 4
+			default:
+				d.pos = i
+				d.fail("in string escape code")
+			}
+			ok = false
+		case c < ' ':
+			d.pos = i
+			d.fail("in string literal")
+		case c >= utf8.RuneSelf:
+			ok = false
+		}
+	}
+	d.pos = len(d.data)
+	d.fail("")
+	return nil, false
+}
+
+func (d *decoder) hasEscape(raw []byte) bool {
+	for _, c := range raw {
+		if c == '\\' {
+			return true
+		}
+	}
+	return false
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// unquote decodes escapes and replaces invalid UTF-8 as encoding/json does.
+func (d *decoder) unquote(start int, raw []byte, ok bool) string {
+	if ok {
+		return string(raw)
+	}
+	b := d.buf[:0]
+	for i := 0; i < len(raw); {
+		switch c := raw[i]; {
+		case c == '\\':
+			switch e := raw[i+1]; e {
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'u':
+				r := getu4(raw[i:])
+				i += 6
+				if utf16.IsSurrogate(r) {
+					if dec := utf16.DecodeRune(r, getu4(raw[i:])); dec != unicode.ReplacementChar {
+						b = utf8.AppendRune(b, dec)
+						i += 6
+						continue
+					}
+					r = unicode.ReplacementChar
+				}
+				b = utf8.AppendRune(b, r)
+				continue
+			default:
+				b = append(b, e)
+			}
+			i += 2
+		case c < utf8.RuneSelf:
+			b = append(b, c)
+			i++
+		default:
+			r, size := utf8.DecodeRune(raw[i:])
+			b = utf8.AppendRune(b, r)
+			i += size
+		}
+	}
+	d.buf = b
+	return string(b)
+}
+
+// getu4 decodes \uXXXX from the beginning of s, returning -1 if it is not present.
+func getu4(s []byte) rune {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return -1
+	}
+	r, err := strconv.ParseUint(string(s[2:6]), 16, 64)
+	if err != nil {
+		return -1
+	}
+	return rune(r)
+}
+
+func (d *decoder) number() any {
+	start := d.pos
+	if d.data[d.pos] == '-' {
+		d.pos++
+	}
+	switch {
+	case d.pos < len(d.data) && d.data[d.pos] == '0':
+		d.pos++
+	case d.pos < len(d.data) && d.data[d.pos] >= '1' && d.data[d.pos] <= '9':
+		d.digits()
+	default:
+		d.fail("in numeric literal")
+	}
+	if d.pos < len(d.data) && d.data[d.pos] == '.' {
+		d.pos++
+		if d.digits() == 0 {
+			d.fail("after decimal point in numeric literal")
+		}
+	}
+	if d.pos < len(d.data) && (d.data[d.pos] == 'e' || d.data[d.pos] == 'E') {
+		d.pos++
+		if d.pos < len(d.data) && (d.data[d.pos] == '+' || d.data[d.pos] == '-') {
+			d.pos++
+		}
+		if d.digits() == 0 {
+			d.fail("in exponent of numeric literal")
+		}
+	}
+	lit := string(d.data[start:d.pos])
+	if d.useNumber {
+		return json.Number(lit)
+	}
+	f, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		panic(&json.UnmarshalTypeError{Value: "number " + lit, Type: reflect.TypeFor[float64](), Offset: int64(d.pos)})
+	}
+	return f
+}
+
+func (d *decoder) digits() int {
+	start := d.pos
+	for d.pos < len(d.data) && d.data[d.pos] >= '0' && d.data[d.pos] <= '9' {
+		d.pos++
+	}
+	return d.pos - start
+}
@@ -0,0 +1,251 @@
+package js
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+var decodeSamples = []string{
+	`null`, `true`, `false`, `0`, `-0`, `1.5e3`, `-12.25E-2`, `1e400`, `"aé😀\n\"\\\/"`,
+	`{"a":[1,{"b":null}],"c":"x","a":2}`, `[]`, `{}`, ` [ 1 , 2 ] `, "\"\xff\xfeab\"", `"\ud800x"`, `"\ud800A"`,
+	`{"a" 1}`, `{"a":1,}`, `[1,]`, `[1 2]`, `01`, `-`, `1.`, `1e`, `.5`, `tru`, `nul`, `"\x"`, "\"\x01\"", `"\u12"`,
+	`{"a":1}}`, `{1:2}`, `[`, `"abc`, `{"a":`, ``, `   `, `NaN`, `+1`, `[1]x`,
+}
+
+func TestDecodeJSON(t *testing.T) {
+	for _, s := range decodeSamples {
+		for _, useNumber := range []bool{false, true} {
+			res, err := decodeJSON([]byte(s), useNumber)
+			exp, expErr := oracleDecode([]byte(s), useNumber)
+			require(t, (err == nil) == (expErr == nil))
+			require(t, err != nil || reflect.DeepEqual(res, exp))
+		}
+	}
+	v, _ := decodeJSON([]byte(`[{"key":1},{"key":2}]`), false)
+	keys := make([]string, 0, 2)
+	for _, o := range v.([]any) {
+		for k := range o.(map[string]any) {
+			keys = append(keys, k)
+		}
+	}
+	require(t, unsafe.StringData(keys[0]) == unsafe.StringData(keys[1])) // interned
+}
+
+func TestDecodeJSON_Errors(t *testing.T) {
+	for s, msg := range map[string]string{
+		`{"a" 1}`:     "js: invalid character '1' after object key at line 1, column 6",
+		`[1 2]`:       "js: invalid character '2' after array element at line 1, column 4",
+		"{\n\"a\":x}": "js: invalid character 'x' looking for beginning of value at line 2, column 5",
+		`[1,2`:        "js: unexpected end of JSON input at line 1, column 5",
+		`"\q"`:        "js: invalid character 'q' in string escape code at line 1, column 3",
+		`tRue`:        "js: invalid character 'R' in literal true (expecting 'r') at line 1, column 2",
+		`{"a":1}x`:    "js: invalid character 'x' after top-level value at line 1, column 8",
+		`-x`:          "js: invalid character 'x' in numeric literal at line 1, column 2",
+	} {
+		_, err := decodeJSON([]byte(s), false)
+		var se *SyntaxError
+		require(t, errors.As(err, &se) && err.Error() == msg)
+	}
+	_, err := decodeJSON(bytes.Repeat([]byte("["), 10001), false)
+	require(t, err != nil && err.(*SyntaxError).Msg == "exceeded max depth")
+}
+
+type decodeBase struct {
+	ID   int `json:"id"`
+	Name string
+}
+
+type decodeTarget struct {
+	decodeBase
+	*DecodeExtra
+	Title   string           `json:"title,omitempty"`
+	Price   float64          `json:"price"`
+	Small   float32          `json:"small"`
+	Count   uint8            `json:"count"`
+	Tags    []string         `json:"tags"`
+	Pair    [2]int           `json:"pair"`
+	Attrs   map[string]int   `json:"attrs"`
+	Named   map[myKey]string `json:"named"`
+	Nested  *decodeBase      `json:"nested"`
+	Any     any              `json:"any"`
+	Time    time.Time        `json:"time"`
+	Num     json.Number      `json:"num"`
+	Data    []byte           `json:"data"`
+	Val     Value            `json:"val"`
+	Obj     Object           `json:"obj"`
+	Skip    string           `json:"-"`
+	Ints    map[int]bool     `json:"ints"`
+	Nums    []float64        `json:"nums"`
+	private int
+}
+
+type DecodeExtra struct {
+	Extra string `json:"extra"`
+}
+
+type myKey string
+
+func TestMarshalTo_Direct(t *testing.T) {
+	obj := Object{
+		"id": 7, "name": "n", "extra": "e", "TITLE": "t", "price": 1.5, "small": 0.1, "count": 200,
+		"tags": Array{"a", "b"}, "pair": []any{1, 2, 3}, "attrs": Object{"x": 1.0},
+		"named": map[string]any{"k": "v"}, "nested": Object{"id": 1}, "any": Object{"a": Array{json.Number("1"), int64(2)}},
+		"time": "2024-01-02T03:04:05Z", "num": 12, "data": "aGk=", "val": Object{"x": true},
+		"obj": Object{"y": nil}, "Skip": "no", "ints": Object{"1": true}, "nums": []int{1, 2}, "private": 1,
+	}
+	var res decodeTarget
+	require(t, obj.MarshalTo(&res) == nil)
+
+	var exp decodeTarget
+	require(t, json.Unmarshal(obj.Bytes(), &exp) == nil)
+	require(t, reflect.DeepEqual(res, exp))
+	require(t, res.ID == 7 && res.Extra == "e" && res.Title == "t" && res.Pair == [2]int{1, 2} && res.Named["k"] == "v")
+	require(t, res.Time.Year() == 2024 && string(res.Data) == "hi" && res.Val.Object().GetBool("x") && res.Ints[1])
+	require(t, reflect.DeepEqual(res.Any, map[string]any{"a": []any{1.0, 2.0}}))
+
+	// the tree is not shared
+	res.Obj["y"] = 1
+	require(t, obj.GetObj("obj")["y"] == nil)
+
+	// type mismatches are reported, the other fields are decoded
+	var bad decodeTarget
+	err := Object{"id": "x", "count": 300, "price": 2.0, "nested": Object{"id": 1.5}}.MarshalTo(&bad)
+	var te *json.UnmarshalTypeError
+	require(t, errors.As(err, &te) && bad.Price == 2)
+	require(t, NewValue(1.5).MarshalTo(new(int)) != nil)
+	require(t, NewValue(1.0).MarshalTo(nil) != nil)
+
+	// null
+	ptr := &decodeBase{}
+	require(t, NewValue(nil).MarshalTo(&ptr) == nil && ptr == nil)
+	m := map[string]int{"a": 1}
+	require(t, Object(nil).MarshalTo(&m) == nil && m == nil)
+
+	// slices are reused like by encoding/json
+	s := make([]int, 1, 10)
+	require(t, Array{1, 2}.MarshalTo(&s) == nil && len(s) == 2 && cap(s) == 10)
+	require(t, Array{}.MarshalTo(&s) == nil && s != nil && len(s) == 0)
+}
+
+func TestMarshalTo_Oracle(t *testing.T) {
+	for _, s := range []string{
+		`{"id":1,"ID":2,"Id":3}`, `{"tags":"x","pair":{},"attrs":[1]}`, `{"count":-1,"small":1e300}`,
+		`{"any":{"a":"\ud800"},"nested":null,"tags":null}`, `{"price":1e21,"id":1e21,"count":255}`,
+		`{"num":"12","time":1,"data":1}`, `[1,2]`, `"x"`, `null`,
+	} {
+		v := MustParse([]byte(s))
+		var res, exp decodeTarget
+		err := v.MarshalTo(&res)
+		expErr := json.Unmarshal(v.Bytes(), &exp)
+		require(t, (err == nil) == (expErr == nil))
+		require(t, err != nil || reflect.DeepEqual(res, exp))
+	}
+}
+
+func FuzzDecodeJSON(f *testing.F) {
+	for _, s := range decodeSamples {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, useNumber := range []bool{false, true} {
+			res, err := decodeJSON(data, useNumber)
+			exp, expErr := oracleDecode(data, useNumber)
+			if (err == nil) != (expErr == nil) {
+				t.Fatalf("%q: error %v, encoding/json: %v", data, err, expErr)
+			}
+			if err == nil && !reflect.DeepEqual(res, exp) {
+				t.Fatalf("%q: %#v, encoding/json: %#v", data, res, exp)
+			}
+		}
+	})
+}
+
+func FuzzMarshalTo(f *testing.F) {
+	f.Add([]byte(`{"id":1,"name":"x","tags":["a"],"attrs":{"a":1},"pair":[1,2],"nested":{"ID":2}}`))
+	f.Add([]byte(`{"id":1.5,"count":256,"small":0.1,"any":[1,{"a":null}],"time":"2024-01-01T00:00:00Z"}`))
+	f.Add([]byte(`{"Name":"a","name":"b","NAME":"c","extra":"e","obj":{"a":[]},"val":[true]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := Parse(data)
+		if err != nil {
+			return
+		}
+		var res, exp decodeTarget
+		err = v.MarshalTo(&res)
+		expErr := json.Unmarshal(v.Bytes(), &exp)
+		if (err == nil) != (expErr == nil) {
+			t.Fatalf("%q: error %v, encoding/json: %v", data, err, expErr)
+		}
+		if err == nil && !reflect.DeepEqual(res, exp) {
+			t.Fatalf("%q: %#v, encoding/json: %#v", data, res, exp)
+		}
+	})
+}
+
+func oracleDecode(data []byte, useNumber bool) (v any, err error) {
+	if !useNumber {
+		err = json.Unmarshal(data, &v)
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&v); err == nil {
+		if _, e := dec.Token(); e != io.EOF {
+			return nil, errors.New("trailing data")
+		}
+	}
+	if err != nil {
+		v = nil
+	}
+	return
+}
+
+func BenchmarkParse(b *testing.B) {
+	data := benchmarkDocument()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = Parse(data)
+	}
+}
+
+func BenchmarkParse_encodingJSON(b *testing.B) {
+	data := benchmarkDocument()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		var v any
+		_ = json.Unmarshal(data, &v)
+	}
+}
+
+type benchmarkBook struct {
+	Symbol       string      `json:"symbol"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+	LastUpdateID int64       `json:"lastUpdateId"`
+	TS           int64       `json:"ts"`
+}
+
+func BenchmarkMarshalTo(b *testing.B) {
+	v := MustParse(benchmarkDocument())
+	b.ReportAllocs()
+	for b.Loop() {
+		var book benchmarkBook
+		_ = v.MarshalTo(&book)
+	}
+}
+
+func BenchmarkMarshalTo_encodingJSON(b *testing.B) {
+	v := MustParse(benchmarkDocument())
+	b.ReportAllocs()
+	for b.Loop() {
+		var book benchmarkBook
+		_ = json.Unmarshal(v.Bytes(), &book)
+	}
+}
@@ -0,0 +1,254 @@
+package js
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ChangeKind is the kind of a Change between two values.
+type ChangeKind int
+
+const (
+	ChangeAdded       ChangeKind = iota // the value exists only in the new document
+	ChangeRemoved                       // the value exists only in the old document
+	ChangeModified                      // the value changed, keeping its type
+	ChangeTypeChanged                   // the value changed its type
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "changed"
+	case ChangeTypeChanged:
+		return "type-changed"
+	}
+	return "unknown"
+}
+
+// Change describes a difference between two values at a path
+// like `items[2].price` (see Value.Path); the root path is empty.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  Value // empty for ChangeAdded
+	New  Value // empty for ChangeRemoved
+}
+
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "$"
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return "+ " + path + ": " + c.New.JSON()
+	case ChangeRemoved:
+		return "- " + path + ": " + c.Old.JSON()
+	}
+	return "- " + path + ": " + c.Old.JSON() + "\n+ " + path + ": " + c.New.JSON()
+}
+
+// DiffOptions configures DiffValuesWith.
+type DiffOptions struct {
+	// IgnorePaths lists paths whose subtrees are not compared.
+	// A `*` segment matches any key or index, e.g. `items[*].updatedAt`
+	// or `[*].id`. DiffValuesWith reports an invalid path as an error.
+	IgnorePaths []string
+
+	// UnorderedArrays compares arrays as multisets, ignoring element order.
+	UnorderedArrays bool
+
+	// ArrayKey matches elements of arrays of objects by the value of this
+	// field (e.g. "id") instead of by position.
+	ArrayKey string
+}
+
+// DiffValues returns the structural differences between a and b.
+func DiffValues(a, b Value) []Change {
+	changes, _ := DiffValuesWith(a, b, DiffOptions{})
+	return changes
+}
+
+// DiffValuesWith returns the structural differences between a and b.
+// It fails only if opts.IgnorePaths contains an invalid path.
+func DiffValuesWith(a, b Value, opts DiffOptions) ([]Change, error) {
+	d := &differ{opts: opts}
+	for _, p := range opts.IgnorePaths {
+		segs, err := parsePath(strings.TrimPrefix(strings.ReplaceAll(p, "[*]", ".*"), "."))
+		if err != nil {
+			return nil, err
+		}
+		d.ignore = append(d.ignore, segs)
+	}
+	d.diff(nil, a.val, b.val)
+	return d.changes, nil
+}
+
+// FormatChanges renders changes as unified-diff-like text:
+// `- path: old` and `+ path: new` lines, colored with ANSI codes if color is set.
+func FormatChanges(changes []Change, color bool) string {
+	var sb strings.Builder
+	for _, c := range changes {
+		for _, line := range strings.Split(c.String(), "\n") {
+			switch {
+			case !color:
+				sb.WriteString(line)
+			case line[0] == '-':
+				sb.WriteString("\x1b[31m" + line + "\x1b[0m")
+			default:
+				sb.WriteString("\x1b[32m" + line + "\x1b[0m")
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+type differ struct {
+	opts    DiffOptions
+	ignore  [][]pathSegment
+	changes []Change
+}
+
+func (d *differ) add(path []pathSegment, kind ChangeKind, old, new any) {
+	d.changes = append(d.changes, Change{formatPath(path), kind, Value{old}, Value{new}})
+}
+
+func (d *differ) ignored(path []pathSegment) bool {
+	for _, pattern := range d.ignore {
+		if len(pattern) == len(path) && matchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPath(pattern, path []pathSegment) bool {
+	for i, p := range pattern {
+		switch s := path[i]; {
+		case !p.isIndex && p.key == "*":
+		case p.isIndex:
+			if !s.isIndex || s.index != p.index {
+				return false
+			}
+		case s.isIndex: // `items.0` matches `items[0]`
+			if p.key != strconv.Itoa(s.index) {
+				return false
+			}
+		default:
+			if p.key != s.key {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (d *differ) diff(path []pathSegment, a, b any) {
+	if d.ignored(path) || deepEqual(a, b) {
+		return
+	}
+	ta, tb := typeName(a), typeName(b)
+	switch {
+	case ta != tb:
+		d.add(path, ChangeTypeChanged, a, b)
+	case ta == "object":
+		oa, _ := asObject(a)
+		ob, _ := asObject(b)
+		keys := oa.Clone().Extend(ob).Keys()
+		for _, k := range keys {
+			p := append(path[:len(path):len(path)], pathSegment{key: k})
+			va, inA := oa[k]
+			vb, inB := ob[k]
+			switch {
+			case d.ignored(p):
+			case !inB:
+				d.add(p, ChangeRemoved, va, nil)
+			case !inA:
+				d.add(p, ChangeAdded, nil, vb)
+			default:
+				d.diff(p, va, vb)
+			}
+		}
+	case ta == "array":
+		aa, _ := asArray(a)
+		bb, _ := asArray(b)
+		d.diffArrays(path, aa, bb)
+	default:
+		d.add(path, ChangeModified, a, b)
+	}
+}
+
+func (d *differ) diffArrays(path []pathSegment, a, b Array) {
+	at := func(i int) []pathSegment {
+		return append(path[:len(path):len(path)], pathSegment{index: i, isIndex: true})
+	}
+	var edits []arrayEdit
+	switch {
+	case d.opts.ArrayKey != "" && d.keyed(a) && d.keyed(b):
+		edits = d.matchArrays(a, b, func(x, y any) bool {
+			ox, _ := asObject(x)
+			oy, _ := asObject(y)
+			return deepEqual(ox[d.opts.ArrayKey], oy[d.opts.ArrayKey])
+		})
+	case d.opts.UnorderedArrays:
+		edits = d.matchArrays(a, b, deepEqual)
+	default:
+		edits = alignArrays(a, b)
+	}
+	for _, e := range edits {
+		switch {
+		case e.same:
+		case e.i < 0:
+			if p := at(e.j); !d.ignored(p) {
+				d.add(p, ChangeAdded, nil, b[e.j])
+			}
+		case e.j < 0:
+			if p := at(e.i); !d.ignored(p) {
+				d.add(p, ChangeRemoved, a[e.i], nil)
+			}
+		default:
+			d.diff(at(e.j), a[e.i], b[e.j])
+		}
+	}
+}
+
+// keyed reports whether all elements of arr are objects having the ArrayKey field.
+func (d *differ) keyed(arr Array) bool {
+	for _, v := range arr {
+		if obj, ok := mergeObject(v); !ok || !obj.Has(d.opts.ArrayKey) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchArrays pairs each element of b with the first unmatched element of a
+// for which match is true, regardless of position.
+func (d *differ) matchArrays(a, b Array, match func(x, y any) bool) (edits []arrayEdit) {
+	used := make([]bool, len(a))
+	var added []arrayEdit
+	for j, y := range b {
+		found := false
+		for i, x := range a {
+			if !used[i] && match(x, y) {
+				used[i], found = true, true
+				edits = append(edits, arrayEdit{i, j, false})
+				break
+			}
+		}
+		if !found {
+			added = append(added, arrayEdit{-1, j, false})
+		}
+	}
+	for i := range a {
+		if !used[i] {
+			edits = append(edits, arrayEdit{i, -1, false})
+		}
+	}
+	return append(edits, added...)
+}
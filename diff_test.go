@@ -0,0 +1,68 @@
+package js
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffValues(t *testing.T) {
+	a := MustParse([]byte(`{"id":1,"name":"Alice","tags":["a","b"],"addr":{"city":"X","zip":1},"age":30}`))
+	b := MustParse([]byte(`{"id":"1","name":"Bob","tags":["a","c","d"],"addr":{"city":"X"},"email":"b@x"}`))
+
+	changes := DiffValues(a, b)
+
+	require(t, FormatChanges(changes, false) == strings.Join([]string{
+		`- addr.zip: 1`,
+		`- age: 30`,
+		`+ email: "b@x"`,
+		`- id: 1`,
+		`+ id: "1"`,
+		`- name: "Alice"`,
+		`+ name: "Bob"`,
+		`- tags[1]: "b"`,
+		`+ tags[1]: "c"`,
+		`+ tags[2]: "d"`,
+	}, "\n")+"\n")
+	require(t, changes[0].Kind == ChangeRemoved && changes[0].Old.Int() == 1 && changes[0].New.IsNull())
+	require(t, changes[2].Kind == ChangeAdded)
+	require(t, changes[3].Kind == ChangeTypeChanged && changes[3].Kind.String() == "type-changed")
+	require(t, changes[4].Kind == ChangeModified && changes[4].Path == "name")
+	require(t, len(DiffValues(a, a)) == 0)
+}
+
+func TestDiffValues_reorderedKeys(t *testing.T) {
+	a := MustParse([]byte(`{"a":1,"b":{"x":1,"y":2}}`))
+	b := MustParse([]byte(`{"b":{"y":2,"x":1},"a":1}`))
+
+	require(t, len(DiffValues(a, b)) == 0)
+}
+
+func TestDiffValuesWith(t *testing.T) {
+	a := MustParse([]byte(`{"items":[{"id":1,"v":1,"ts":1},{"id":2,"v":2,"ts":1}],"set":[1,2,3],"ts":1}`))
+	b := MustParse([]byte(`{"items":[{"id":3,"v":3,"ts":2},{"id":2,"v":2,"ts":2},{"id":1,"v":10,"ts":2}],"set":[3,1,2],"ts":2}`))
+
+	changes, err := DiffValuesWith(a, b, DiffOptions{
+		IgnorePaths:     []string{"ts", "items[*].ts"},
+		UnorderedArrays: true,
+		ArrayKey:        "id",
+	})
+
+	require(t, err == nil && FormatChanges(changes, false) == "- items[2].v: 1\n+ items[2].v: 10\n+ items[0]: {\"id\":3,\"ts\":2,\"v\":3}\n")
+
+	// a leading wildcard matches the elements of a top-level array
+	a, b = MustParse([]byte(`[{"id":1,"v":1}]`)), MustParse([]byte(`[{"id":2,"v":1}]`))
+	changes, err = DiffValuesWith(a, b, DiffOptions{IgnorePaths: []string{"[*].id"}})
+	require(t, err == nil && len(changes) == 0)
+	changes, err = DiffValuesWith(a, b, DiffOptions{IgnorePaths: []string{"[0].v"}})
+	require(t, err == nil && len(changes) == 1)
+
+	changes, err = DiffValuesWith(a, b, DiffOptions{IgnorePaths: []string{"id", "a[x"}})
+	require(t, changes == nil && err != nil && strings.Contains(err.Error(), "unclosed `[`"))
+}
+
+func TestFormatChanges_color(t *testing.T) {
+	changes := DiffValues(NewValue(1), NewValue(2))
+
+	require(t, changes[0].Path == "")
+	require(t, FormatChanges(changes, true) == "\x1b[31m- $: 1\x1b[0m\n\x1b[32m+ $: 2\x1b[0m\n")
+}
@@ -0,0 +1,376 @@
+package js
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxEncodeDepth limits the nesting of Go values converted by encodeTree,
+// which stops cyclic values.
+const maxEncodeDepth = 10000
+
+// encodeTree converts a Go value to a tree of map[string]any, []any and scalars,
+// as Parse would decode its JSON encoding, following the same rules
+// as json.Marshal (json tags, omitempty, omitzero, the string option,
+// embedded structs, json.Marshaler and encoding.TextMarshaler implementers).
+// Unlike the JSON round-trip, time.Time, []byte and integers keep their types.
+func encodeTree(v any) (any, error) {
+	e := encoder{}
+	return e.value(v)
+}
+
+type encoder struct {
+	depth int
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func (e *encoder) value(v any) (any, error) {
+	switch val := v.(type) {
+	case nil, bool, string, float64, int, int64, uint64, json.Number, time.Time:
+		return val, nil
+	case Value:
+		return e.value(val.val)
+	case Object:
+		return e.object(val)
+	case map[string]any:
+		return e.object(val)
+	case Array:
+		return e.array(val)
+	case []any:
+		return e.array(val)
+	}
+	return e.reflect(reflect.ValueOf(v))
+}
+
+func (e *encoder) enter(rv reflect.Value) error {
+	if e.depth++; e.depth > maxEncodeDepth {
+		return &json.UnsupportedValueError{Value: rv, Str: fmt.Sprintf("encountered a cycle via %s", rv.Type())}
+	}
+	return nil
+}
+
+func (e *encoder) object(obj map[string]any) (any, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	if err := e.enter(reflect.ValueOf(obj)); err != nil {
+		return nil, err
+	}
+	defer func() { e.depth-- }()
+	res := make(map[string]any, len(obj))
+	for k, v := range obj {
+		val, err := e.value(v)
+		if err != nil {
+			return nil, err
+		}
+		res[k] = val
+	}
+	return res, nil
+}
+
+func (e *encoder) array(arr []any) (any, error) {
+	if arr == nil {
+		return nil, nil
+	}
+	if err := e.enter(reflect.ValueOf(arr)); err != nil {
+		return nil, err
+	}
+	defer func() { e.depth-- }()
+	res := make([]any, len(arr))
+	for i, v := range arr {
+		val, err := e.value(v)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = val
+	}
+	return res, nil
+}
+
+var marshalerCache sync.Map // reflect.Type -> marshalerKind
+
+type marshalerKind int
+
+const (
+	noMarshaler marshalerKind = iota
+	jsonMarshaler
+	textMarshaler
+	addrJSONMarshaler // implemented by the pointer type
+	addrTextMarshaler
+)
+
+func typeMarshaler(t reflect.Type) marshalerKind {
+	if k, ok := marshalerCache.Load(t); ok {
+		return k.(marshalerKind)
+	}
+	k := noMarshaler
+	switch {
+	case t == timeType:
+	case t.Implements(jsonMarshalerType):
+		k = jsonMarshaler
+	case t.Implements(textMarshalerType):
+		k = textMarshaler
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(jsonMarshalerType):
+		k = addrJSONMarshaler
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(textMarshalerType):
+		k = addrTextMarshaler
+	}
+	marshalerCache.Store(t, k)
+	return k
+}
+
+func (e *encoder) reflect(rv reflect.Value) (any, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	t := rv.Type()
+	switch typeMarshaler(t) {
+	case jsonMarshaler:
+		return e.marshalJSON(rv)
+	case textMarshaler:
+		return e.marshalText(rv)
+	case addrJSONMarshaler:
+		if rv.CanAddr() {
+			return e.marshalJSON(rv.Addr())
+		}
+	case addrTextMarshaler:
+		if rv.CanAddr() {
+			return e.marshalText(rv.Addr())
+		}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		if t == jsonNumberType {
+			return rv.Interface(), nil
+		}
+		return rv.String(), nil
+	case reflect.Int:
+		return int(rv.Int()), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint:
+		return uint(rv.Uint()), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		s, ok := formatFloat(rv.Float(), t.Bits())
+		if !ok {
+			return nil, &json.UnsupportedValueError{Value: rv, Str: strconv.FormatFloat(rv.Float(), 'g', -1, t.Bits())}
+		}
+		if t.Kind() == reflect.Float64 {
+			return rv.Float(), nil
+		}
+		return strconv.ParseFloat(s, 64) // the float64 nearest to the shortest decimal form
+	case reflect.Struct:
+		if t == timeType {
+			return rv.Interface(), nil
+		}
+		return e.structValue(rv)
+	case reflect.Map:
+		return e.mapValue(rv)
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && typeMarshaler(t.Elem()) == noMarshaler {
+			return bytes.Clone(rv.Bytes()), nil
+		}
+		return e.arrayValue(rv)
+	case reflect.Array:
+		return e.arrayValue(rv)
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if err := e.enter(rv); err != nil {
+			return nil, err
+		}
+		defer func() { e.depth-- }()
+		if t.Kind() == reflect.Interface {
+			return e.value(rv.Elem().Interface())
+		}
+		return e.reflect(rv.Elem())
+	}
+	return nil, &json.UnsupportedTypeError{Type: t}
+}
+
+func (e *encoder) marshalJSON(rv reflect.Value) (any, error) {
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	data, err := rv.Interface().(json.Marshaler).MarshalJSON()
+	if err == nil {
+		var v any
+		if v, err = decodeJSON(data, false); err == nil {
+			return v, nil
+		}
+	}
+	return nil, &json.MarshalerError{Type: rv.Type(), Err: err}
+}
+
+func (e *encoder) marshalText(rv reflect.Value) (any, error) {
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, &json.MarshalerError{Type: rv.Type(), Err: err}
+	}
+	return string(text), nil
+}
+
+func (e *encoder) structValue(rv reflect.Value) (any, error) {
+	sf := cachedFields(rv.Type())
+	res := make(map[string]any, len(sf.list))
+	for i := range sf.list {
+		f := &sf.list[i]
+		fv, ok := embeddedField(rv, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) || f.omitZero && isZeroValue(fv) {
+			continue
+		}
+		val, err := e.reflect(fv)
+		if err != nil {
+			return nil, err
+		}
+		if f.quoted && val != nil {
+			if val, err = quotedValue(val); err != nil {
+				return nil, err
+			}
+		}
+		res[f.name] = val
+	}
+	return res, nil
+}
+
+// embeddedField returns the field of v at index; it fails at a nil embedded pointer.
+func embeddedField(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// quotedValue applies the string option of a field: the JSON encoding
+// of a bool, number or string becomes a string.
+func quotedValue(v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		data, err := json.Marshal(val)
+		return string(data), err
+	case bool:
+		return strconv.FormatBool(val), nil
+	}
+	if lit, ok := numberLiteral(v); ok {
+		return lit, nil
+	}
+	return v, nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// isZeroValue reports whether v is zero for the omitzero option:
+// by its IsZero method if it has one.
+func isZeroValue(v reflect.Value) bool {
+	type zeroer interface{ IsZero() bool }
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return true
+	}
+	if z, ok := v.Interface().(zeroer); ok {
+		return z.IsZero()
+	}
+	if v.CanAddr() {
+		if z, ok := v.Addr().Interface().(zeroer); ok {
+			return z.IsZero()
+		}
+	}
+	return v.IsZero()
+}
+
+func (e *encoder) mapValue(rv reflect.Value) (any, error) {
+	if rv.IsNil() {
+		return nil, nil
+	}
+	if err := e.enter(rv); err != nil {
+		return nil, err
+	}
+	defer func() { e.depth-- }()
+	res := make(map[string]any, rv.Len())
+	for it := rv.MapRange(); it.Next(); {
+		key, err := mapKey(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		val, err := e.reflect(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		res[key] = val
+	}
+	return res, nil
+}
+
+// mapKey converts a map key to a string, as encoding/json does.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		text, err := tm.MarshalText()
+		if err != nil {
+			return "", &json.MarshalerError{Type: k.Type(), Err: err}
+		}
+		return string(text), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+func (e *encoder) arrayValue(rv reflect.Value) (any, error) {
+	if err := e.enter(rv); err != nil {
+		return nil, err
+	}
+	defer func() { e.depth-- }()
+	res := make([]any, rv.Len())
+	for i := range res {
+		val, err := e.reflect(rv.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		res[i] = val
+	}
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package js
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type encodeInner struct {
+	Note string `json:"note,omitempty"`
+}
+
+type encodeColor int
+
+func (c encodeColor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"rgb": int(c)})
+}
+
+func (c *encodeColor) UnmarshalJSON(data []byte) error {
+	*c = encodeColor(MustParseObject(data).GetInt("rgb"))
+	return nil
+}
+
+type encodeSource struct {
+	encodeInner
+	*DecodeExtra
+	ID       int64             `json:"id"`
+	Big      uint64            `json:"big"`
+	Name     string            `json:"name,omitempty"`
+	Empty    []int             `json:"empty,omitempty"`
+	Zero     time.Time         `json:"zero,omitzero"`
+	At       time.Time         `json:"at"`
+	Count    int               `json:"count,string"`
+	Quoted   string            `json:"quoted,string"`
+	Ratio    float32           `json:"ratio"`
+	Data     []byte            `json:"data"`
+	Color    encodeColor       `json:"color"`
+	Addr     netip.Addr        `json:"addr"`
+	Ptr      *encodeInner      `json:"ptr"`
+	Ints     map[int]string    `json:"ints"`
+	Any      any               `json:"any"`
+	Obj      Object            `json:"obj"`
+	Tags     []string          `json:"tags"`
+	Pair     [2]bool           `json:"pair"`
+	Attrs    map[string]string `json:"attrs"`
+	Skip     string            `json:"-"`
+	Dash     string            `json:"-,"`
+	internal int
+}
+
+func TestNewObject_Struct(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	src := encodeSource{
+		encodeInner: encodeInner{"n"}, ID: 1<<62 + 1, Big: math.MaxUint64, At: at, Count: 12, Quoted: "q",
+		Ratio: 0.1, Data: []byte("hi"), Color: 255, Addr: netip.MustParseAddr("10.0.0.1"),
+		Ints: map[int]string{1: "a"}, Any: []encodeInner{{"x"}}, Obj: Object{"s": encodeInner{"y"}},
+		Tags: []string{"a"}, Attrs: map[string]string{"k": "v"}, Skip: "s", Dash: "d", internal: 1,
+	}
+	obj := NewObject(src)
+
+	require(t, obj["id"] == int64(1<<62+1) && obj.GetUint64("big") == math.MaxUint64)
+	require(t, obj["at"] == at && obj.GetTime("at").Equal(at) && obj.GetStr("at") == "2024-01-02T03:04:05.000000006Z")
+	require(t, obj.GetStr("note") == "n" && !obj.Has("name") && !obj.Has("empty") && !obj.Has("zero"))
+	require(t, !obj.Has("extra") && obj.Has("ptr") && obj["ptr"] == nil)
+	require(t, obj["count"] == "12" && obj["quoted"] == `"q"` && obj["ratio"] == 0.1)
+	require(t, string(obj["data"].([]byte)) == "hi" && obj.GetObj("color").GetInt("rgb") == 255)
+	require(t, obj["addr"] == "10.0.0.1" && obj.GetObj("ints").GetStr("1") == "a")
+	require(t, obj.GetArr("any").Eq(0).Object().GetStr("note") == "x")
+	require(t, obj.GetObj("obj").GetObj("s").GetStr("note") == "y")
+	require(t, !obj.Has("Skip") && obj.GetStr("-") == "d" && !obj.Has("internal"))
+
+	// time.Time is formatted as in JSON, as it was when structs were converted through JSON
+	require(t, `"`+ToStr(at)+`"` == string(must(json.Marshal(at))) && NewValue(at).String() == obj.GetStr("at"))
+
+	// the same JSON as json.Marshal
+	exp := MustParse(must(json.Marshal(src)))
+	require(t, deepEqual(MustParse(obj.Bytes()).val, exp.val))
+
+	// and back
+	var res encodeSource
+	require(t, obj.DecodeTo(&res) == nil)
+	require(t, NewValue(res.Any).Array().Eq(0).Object().GetStr("note") == "x")
+	require(t, res.Obj.GetObj("s").GetStr("note") == "y")
+	src.Skip, src.internal = "", 0
+	res.Any, res.Obj = src.Any, src.Obj
+	require(t, reflect.DeepEqual(res, src))
+}
+
+func TestNewObject_Errors(t *testing.T) {
+	_, err := toObject(struct{ F float64 }{math.NaN()})
+	var ue *json.UnsupportedValueError
+	require(t, errors.As(err, &ue))
+
+	_, err = toObject(struct{ C chan int }{})
+	var te *json.UnsupportedTypeError
+	require(t, errors.As(err, &te))
+
+	type node struct{ Next *node }
+	n := &node{}
+	n.Next = n
+	_, err = toObject(n)
+	require(t, errors.As(err, &ue) && strings.Contains(err.Error(), "cycle"))
+
+	_, err = toObject([]int{1})
+	require(t, err != nil)
+	require(t, newObject("str") == nil && NewObject(nil) == nil && NewObject((*node)(nil)) == nil)
+}
+
+func BenchmarkNewObject(b *testing.B) {
+	src := encodeSource{ID: 1, Name: "n", At: time.Now(), Tags: []string{"a", "b"}, Attrs: map[string]string{"k": "v"}}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = NewObject(src)
+	}
+}
+
+func BenchmarkNewObject_encodingJSON(b *testing.B) {
+	src := encodeSource{ID: 1, Name: "n", At: time.Now(), Tags: []string{"a", "b"}, Attrs: map[string]string{"k": "v"}}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = ParseObject(must(json.Marshal(src)))
+	}
+}
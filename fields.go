@@ -0,0 +1,178 @@
+package js
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// structField is a JSON field of a struct type, resolved as encoding/json does.
+type structField struct {
+	name      string
+	tagged    bool  // the name comes from a json tag
+	index     []int // path of field indexes through embedded structs
+	typ       reflect.Type
+	omitEmpty bool
+	omitZero  bool
+	quoted    bool // the ",string" option applies
+}
+
+// structFields is the cached field plan of a struct type.
+type structFields struct {
+	list   []structField
+	byName map[string]int
+	quoted bool // some field has the ",string" option
+}
+
+var fieldCache sync.Map // reflect.Type -> *structFields
+
+// cachedFields returns the JSON fields of the struct type t.
+func cachedFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(*structFields)
+}
+
+// lookup returns the field for a key: by exact name or, as encoding/json
+// does, by case-insensitive match.
+func (sf *structFields) lookup(key string) *structField {
+	if i, ok := sf.byName[key]; ok {
+		return &sf.list[i]
+	}
+	for i := range sf.list {
+		if strings.EqualFold(sf.list[i].name, key) {
+			return &sf.list[i]
+		}
+	}
+	return nil
+}
+
+// typeFields follows the rules of encoding/json: exported fields, json tags,
+// fields of embedded structs promoted unless hidden by a shallower
+// or tagged field of the same name.
+func typeFields(t reflect.Type) *structFields {
+	var fields []structField
+	current, next := []structField{}, []structField{{typ: t}}
+	count, nextCount := map[reflect.Type]int{}, map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				if !isValidTag(name) {
+					name = ""
+				}
+				index := append(f.index[:len(f.index):len(f.index)], i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := structField{name: name, tagged: name != "", index: index, typ: ft}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					for _, opt := range strings.Split(opts, ",") {
+						switch opt {
+						case "omitempty":
+							field.omitEmpty = true
+						case "omitzero":
+							field.omitZero = true
+						case "string":
+							switch ft.Kind() {
+							case reflect.Bool, reflect.String,
+								reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+								reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+								reflect.Float32, reflect.Float64:
+								field.quoted = true
+							}
+						}
+					}
+					fields = append(fields, field)
+					if count[f.typ] > 1 {
+						// the struct is embedded more than once at this depth:
+						// the duplicate makes the field ambiguous below
+						fields = append(fields, field)
+					}
+					continue
+				}
+				if nextCount[ft]++; nextCount[ft] == 1 {
+					next = append(next, structField{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(fields, func(a, b structField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+	// keep only the dominant field of each name
+	out := fields[:0]
+	for i, n := 0, 0; i < len(fields); i += n {
+		for n = 1; i+n < len(fields) && fields[i+n].name == fields[i].name; n++ {
+		}
+		if n > 1 && len(fields[i].index) == len(fields[i+1].index) && fields[i].tagged == fields[i+1].tagged {
+			continue // ambiguous: dropped
+		}
+		out = append(out, fields[i])
+	}
+	slices.SortFunc(out, func(a, b structField) int { return slices.Compare(a.index, b.index) })
+
+	res := &structFields{list: out, byName: make(map[string]int, len(out))}
+	for i, f := range out {
+		res.byName[f.name] = i
+		res.quoted = res.quoted || f.quoted
+	}
+	return res
+}
+
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
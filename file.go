@@ -7,17 +7,16 @@ import (
 	"os"
 )
 
-func ParseFile(filename string) (v Value, err error) {
-	err = UnmarshalFile(filename, &v.val)
-	return
+func ParseFile(filename string, opts ...ParseOptions) (v Value, err error) {
+	defer catch(&err)
+	// with MaxSize set, a larger file is not read past the limit
+	v, err = Parse(readInput(must(os.Open(filename)), parseOptions(opts)), opts...)
+	return v, withFile(err, filename)
 }
 
 func UnmarshalFile(filename string, v any) (err error) {
 	defer catch(&err)
-	f := must(os.Open(filename))
-	defer f.Close()
-	check(json.NewDecoder(f).Decode(v))
-	return
+	return withFile(unmarshal(must(os.ReadFile(filename)), v, ParseOptions{}), filename)
 }
 
 func MarshalToFile(filename string, v any) (err error) {
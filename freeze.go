@@ -0,0 +1,240 @@
+package js
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrFrozen is the panic value (wrapped) of mutators of FrozenObject and FrozenArray.
+var ErrFrozen = errors.New("value is frozen")
+
+func frozen(method string) {
+	panic(fmt.Errorf("js: %s: %w", method, ErrFrozen))
+}
+
+// FrozenObject is a read-only snapshot of an object that can be shared
+// between goroutines and callers. Nested objects and arrays are returned
+// as copies (Get, Path, Object) or frozen views (GetObj, GetArr), so the
+// snapshot cannot be changed through them. Set and Delete panic with ErrFrozen.
+type FrozenObject struct {
+	obj Object
+}
+
+// FrozenArray is a read-only snapshot of an array (see FrozenObject).
+// Push, Unshift, Sort, SortBy and Reverse panic with ErrFrozen.
+type FrozenArray struct {
+	arr Array
+}
+
+// Freeze returns a read-only snapshot of a deep copy of the object.
+// Other Go containers inside it (structs, pointers, typed slices and maps
+// like []string) are converted as by NewObject, so they are copied too.
+// Freeze panics if such a value cannot be encoded to JSON.
+func (obj Object) Freeze() FrozenObject {
+	return FrozenObject{freezeObject(obj)}
+}
+
+// Freeze returns a read-only snapshot of a deep copy of the array (see Object.Freeze).
+func (arr Array) Freeze() FrozenArray {
+	return FrozenArray{freezeArray(arr)}
+}
+
+// freezeValue deep-copies v like deepClone, converting the containers
+// that deepClone would share with the caller to generic ones.
+func freezeValue(v any) any {
+	switch val := v.(type) {
+	case nil, string, bool, float64, int, int64, uint64, json.Number, time.Time:
+		return v
+	case map[string]any:
+		return map[string]any(freezeObject(val))
+	case Object:
+		return freezeObject(val)
+	case []any:
+		return []any(freezeArray(val))
+	case Array:
+		return freezeArray(val)
+	case *OrderedObject:
+		return val.Clone()
+	case Value:
+		return Value{freezeValue(val.val)}
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Pointer, reflect.Interface, reflect.Struct:
+		return must(encodeTree(v))
+	}
+	return v
+}
+
+func freezeObject(obj Object) Object {
+	if obj == nil {
+		return nil
+	}
+	res := make(Object, len(obj))
+	for k, v := range obj {
+		res[k] = freezeValue(v)
+	}
+	return res
+}
+
+func freezeArray(arr Array) Array {
+	if arr == nil {
+		return nil
+	}
+	res := make(Array, len(arr))
+	for i, v := range arr {
+		res[i] = freezeValue(v)
+	}
+	return res
+}
+
+// Object returns a mutable deep copy of the object.
+func (f FrozenObject) Object() Object {
+	return deepCloneObject(f.obj)
+}
+
+// Len returns the number of key-value pairs in the object.
+func (f FrozenObject) Len() int {
+	return len(f.obj)
+}
+
+// Has checks if the key exists in the object.
+func (f FrozenObject) Has(key string) bool {
+	return f.obj.Has(key)
+}
+
+// Keys returns the sorted keys of the object.
+func (f FrozenObject) Keys() []string {
+	return f.obj.Keys()
+}
+
+// Get retrieves a copy of the value for the given key.
+func (f FrozenObject) Get(name string) Value {
+	return Value{deepClone(f.obj.get(name))}
+}
+
+// Path retrieves a copy of the nested value by path (see Value.Path).
+func (f FrozenObject) Path(path string) Value {
+	return Value{f.obj}.Path(path).DeepClone()
+}
+
+// GetBool retrieves the boolean value by key.
+func (f FrozenObject) GetBool(name string) bool {
+	return f.obj.GetBool(name)
+}
+
+// GetStr retrieves the string value by key.
+func (f FrozenObject) GetStr(name string) string {
+	return f.obj.GetStr(name)
+}
+
+// GetNum retrieves the numeric value (float64) by key.
+func (f FrozenObject) GetNum(name string) float64 {
+	return f.obj.GetNum(name)
+}
+
+// GetInt retrieves the integer value (int) by key.
+func (f FrozenObject) GetInt(name string) int {
+	return f.obj.GetInt(name)
+}
+
+// GetInt64 retrieves the 64-bit integer value (int64) by key.
+func (f FrozenObject) GetInt64(name string) int64 {
+	return f.obj.GetInt64(name)
+}
+
+// GetTime retrieves the time value (time.Time) by key.
+func (f FrozenObject) GetTime(name string) time.Time {
+	return f.obj.GetTime(name)
+}
+
+// GetObj retrieves the nested object by key as a frozen view.
+func (f FrozenObject) GetObj(name string) FrozenObject {
+	return FrozenObject{f.obj.GetObj(name)}
+}
+
+// GetArr retrieves the nested array by key as a frozen view.
+func (f FrozenObject) GetArr(name string) FrozenArray {
+	return FrozenArray{f.obj.GetArr(name)}
+}
+
+// Set panics with ErrFrozen.
+func (f FrozenObject) Set(name string, v any) FrozenObject {
+	frozen("FrozenObject.Set")
+	return f
+}
+
+// Delete panics with ErrFrozen.
+func (f FrozenObject) Delete(name string) FrozenObject {
+	frozen("FrozenObject.Delete")
+	return f
+}
+
+// String converts the object to a JSON string.
+func (f FrozenObject) String() string {
+	return f.obj.String()
+}
+
+// MarshalJSON encodes the object to JSON.
+func (f FrozenObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.obj)
+}
+
+// Array returns a mutable deep copy of the array.
+func (f FrozenArray) Array() Array {
+	return deepCloneArray(f.arr)
+}
+
+// Len returns the length of the array.
+func (f FrozenArray) Len() int {
+	return len(f.arr)
+}
+
+// Eq retrieves a copy of the value by index (see Array.Eq).
+func (f FrozenArray) Eq(i int) Value {
+	return f.arr.Eq(i).DeepClone()
+}
+
+// Obj retrieves the object by index as a frozen view.
+func (f FrozenArray) Obj(i int) FrozenObject {
+	return FrozenObject{f.arr.Eq(i).Object()}
+}
+
+// Push panics with ErrFrozen.
+func (f FrozenArray) Push(v ...any) FrozenArray {
+	frozen("FrozenArray.Push")
+	return f
+}
+
+// Unshift panics with ErrFrozen.
+func (f FrozenArray) Unshift(v ...any) FrozenArray {
+	frozen("FrozenArray.Unshift")
+	return f
+}
+
+// Sort panics with ErrFrozen.
+func (f FrozenArray) Sort(less func(a, b Value) bool) {
+	frozen("FrozenArray.Sort")
+}
+
+// SortBy panics with ErrFrozen.
+func (f FrozenArray) SortBy(paramName string) {
+	frozen("FrozenArray.SortBy")
+}
+
+// Reverse panics with ErrFrozen.
+func (f FrozenArray) Reverse() {
+	frozen("FrozenArray.Reverse")
+}
+
+// String converts the array to a JSON string.
+func (f FrozenArray) String() string {
+	return f.arr.String()
+}
+
+// MarshalJSON encodes the array to JSON.
+func (f FrozenArray) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.arr)
+}
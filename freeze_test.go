@@ -0,0 +1,77 @@
+package js
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestObject_DeepClone(t *testing.T) {
+	obj := MustParseObject([]byte(`{"a":{"b":[1,{"c":2}]}}`))
+
+	shallow := obj.Clone()
+	deep := obj.DeepClone()
+	obj.GetObj("a").Set("x", 1)
+	obj.GetObj("a").GetArr("b").Eq(1).Object().Set("c", 3)
+
+	require(t, shallow.GetObj("a").Has("x"))
+	require(t, !deep.GetObj("a").Has("x"))
+	require(t, deep.GetPath("a.b[1].c").Int() == 2)
+	require(t, Object(nil).DeepClone() == nil)
+}
+
+func TestArray_DeepClone(t *testing.T) {
+	arr := Array{Object{"a": 1}, Array{1, 2}}
+	clone := arr.DeepClone()
+	arr[0].(Object)["a"] = 2
+	arr[1].(Array)[0] = 5
+
+	require(t, clone.String() == `[{"a":1},[1,2]]`)
+	require(t, NewValue(arr).DeepClone().JSON() == arr.String())
+}
+
+func TestFreeze(t *testing.T) {
+	obj := MustParseObject([]byte(`{"name":"x","cfg":{"port":80},"list":[{"id":1}]}`))
+	f := obj.Freeze()
+	obj.Set("name", "y")
+
+	require(t, f.GetStr("name") == "x")
+	require(t, f.GetObj("cfg").GetInt("port") == 80)
+	require(t, f.Path("list[0].id").Int() == 1)
+	require(t, f.Len() == 3 && f.Has("cfg"))
+
+	// values returned from a frozen object are copies
+	f.Get("cfg").Object().Set("port", 1)
+	f.Path("list[0]").Object().Set("id", 2)
+	f.GetArr("list").Eq(0).Object().Set("id", 3)
+	require(t, f.GetObj("cfg").GetInt("port") == 80)
+	require(t, f.GetArr("list").Obj(0).GetInt("id") == 1)
+	require(t, f.String() == `{"cfg":{"port":80},"list":[{"id":1}],"name":"x"}`)
+	require(t, NewValue(f).JSON() == f.String())
+
+	thawed := f.Object()
+	thawed.GetObj("cfg").Set("port", 1)
+	require(t, f.GetObj("cfg").GetInt("port") == 80)
+
+	err := call(func() { f.Set("a", 1) })
+	require(t, err != nil)
+	require(t, call(func() { f.GetObj("cfg").Delete("port") }) != nil)
+	require(t, call(func() { f.GetArr("list").Push(1) }) != nil)
+	require(t, call(func() { f.GetArr("list").Reverse() }) != nil)
+	require(t, call(func() { f.GetArr("list").Sort(nil) }) != nil)
+
+	// typed Go containers are not shared with the caller
+	tags, attrs := []string{"a", "b"}, map[string]int{"k": 1}
+	type item struct{ Tags []string }
+	f = Object{"tags": tags, "attrs": attrs, "item": &item{tags}}.Freeze()
+	tags[0], attrs["k"] = "x", 2
+	require(t, f.String() == `{"attrs":{"k":1},"item":{"Tags":["a","b"]},"tags":["a","b"]}`)
+	require(t, Array{tags}.Freeze().String() == `[["x","b"]]`)
+}
+
+func TestFreeze_ErrFrozen(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		require(t, errors.Is(err, ErrFrozen))
+	}()
+	Array{1}.Freeze().SortBy("id")
+}
@@ -0,0 +1,142 @@
+package js
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// As converts the value to the Go type T.
+// Numbers, strings, booleans and time.Time are converted by the strict
+// conversions (Value.ToInt64, ToTime, etc.), with overflow checks for sized
+// integer types; Value, Object, Array and any are taken as is. Other types
+// (structs, slices, maps) are decoded from the JSON of the value, as by MarshalTo.
+// Errors are reported as *ConversionError.
+func As[T any](v Value) (res T, err error) {
+	switch p := any(&res).(type) {
+	case *any:
+		*p = v.val
+	case *Value:
+		*p = v
+	case *string:
+		*p, err = v.ToStringStrict()
+	case *bool:
+		*p, err = v.ToBool()
+	case *int:
+		*p, err = asSigned[int](v)
+	case *int8:
+		*p, err = asSigned[int8](v)
+	case *int16:
+		*p, err = asSigned[int16](v)
+	case *int32:
+		*p, err = asSigned[int32](v)
+	case *int64:
+		*p, err = asSigned[int64](v)
+	case *uint:
+		*p, err = asUnsigned[uint](v)
+	case *uint8:
+		*p, err = asUnsigned[uint8](v)
+	case *uint16:
+		*p, err = asUnsigned[uint16](v)
+	case *uint32:
+		*p, err = asUnsigned[uint32](v)
+	case *uint64:
+		*p, err = asUnsigned[uint64](v)
+	case *float64:
+		*p, err = v.ToFloat64()
+	case *float32:
+		var f float64
+		if f, err = v.ToFloat64(); err == nil && math.Abs(f) > math.MaxFloat32 {
+			err = v.convErr("float32", ErrOverflow)
+		}
+		*p = float32(f)
+	case *json.Number:
+		if *p = v.Number(); *p == "" {
+			err = v.convErr("json.Number", nil)
+		}
+	case *time.Time:
+		*p, err = v.ToTime()
+	case *Object:
+		if v.IsObject() {
+			*p = v.Object()
+		} else if !v.IsNull() {
+			err = v.convErr("js.Object", nil)
+		}
+	case *Array:
+		if v.IsArray() {
+			*p = v.Array()
+		} else if !v.IsNull() {
+			err = v.convErr("js.Array", nil)
+		}
+	default:
+		if e := v.MarshalTo(p); e != nil {
+			err = v.convErr(reflect.TypeFor[T]().String(), e)
+		}
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return res, nil
+}
+
+func asSigned[T int | int8 | int16 | int32 | int64](v Value) (T, error) {
+	n, err := v.ToInt64()
+	if err != nil {
+		return 0, retarget[T](err)
+	}
+	if int64(T(n)) != n {
+		return 0, v.convErr(reflect.TypeFor[T]().String(), ErrOverflow)
+	}
+	return T(n), nil
+}
+
+func asUnsigned[T uint | uint8 | uint16 | uint32 | uint64](v Value) (T, error) {
+	n, err := v.ToUint64()
+	if err != nil {
+		return 0, retarget[T](err)
+	}
+	if uint64(T(n)) != n {
+		return 0, v.convErr(reflect.TypeFor[T]().String(), ErrOverflow)
+	}
+	return T(n), nil
+}
+
+// retarget sets the requested type of a *ConversionError to T.
+func retarget[T any](err error) error {
+	if e, ok := err.(*ConversionError); ok {
+		e.To = reflect.TypeFor[T]().String()
+	}
+	return err
+}
+
+// GetAs converts the value of the object key to the Go type T (see As).
+// Returns an error wrapping ErrNotFound if the key is missing.
+func GetAs[T any](obj Object, key string) (T, error) {
+	val, ok := obj[key]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("js: key `%s` %w", key, ErrNotFound)
+	}
+	return As[T](Value{val})
+}
+
+// Must converts the value to the Go type T (see As) and panics on error.
+func Must[T any](v Value) T {
+	return must(As[T](v))
+}
+
+// Default converts the value to the Go type T (see As), returning fallback
+// if the value is null or cannot be converted.
+func Default[T any](v Value, fallback T) T {
+	if v.IsNull() {
+		return fallback
+	}
+	res, err := As[T](v)
+	if err != nil {
+		return fallback
+	}
+	return res
+}
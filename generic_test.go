@@ -0,0 +1,65 @@
+package js
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAs(t *testing.T) {
+	type item struct {
+		ID    int     `json:"id"`
+		Price float64 `json:"price"`
+	}
+	v := MustParse([]byte(`{"n":300,"s":"x","b":true,"t":"2024-01-02","f":1.5,"items":[{"id":1,"price":2.5}],"obj":{"a":1}}`))
+
+	n, err := As[int](v.Path("n"))
+	require(t, err == nil && n == 300)
+	u, err := As[uint16](v.Path("n"))
+	require(t, err == nil && u == 300)
+	s, err := As[string](v.Path("s"))
+	require(t, err == nil && s == "x")
+	b, err := As[bool](v.Path("b"))
+	require(t, err == nil && b)
+	tm, err := As[time.Time](v.Path("t"))
+	require(t, err == nil && tm.Year() == 2024)
+	items, err := As[[]item](v.Path("items"))
+	require(t, err == nil && len(items) == 1 && items[0].Price == 2.5)
+	obj, err := As[Object](v.Path("obj"))
+	require(t, err == nil && obj.GetInt("a") == 1)
+	m, err := As[map[string]int](v.Path("obj"))
+	require(t, err == nil && m["a"] == 1)
+
+	_, err = As[int8](v.Path("n"))
+	var ce *ConversionError
+	require(t, errors.Is(err, ErrOverflow) && errors.As(err, &ce) && ce.To == "int8")
+	_, err = As[int](v.Path("f"))
+	require(t, errors.Is(err, ErrTruncated))
+	_, err = As[uint](v.Path("s"))
+	require(t, errors.As(err, &ce) && ce.To == "uint" && ce.Kind == KindString)
+	_, err = As[[]item](v.Path("obj"))
+	require(t, errors.As(err, &ce) && ce.To == "[]js.item")
+	_, err = As[Array](v.Path("obj"))
+	require(t, err != nil)
+}
+
+func TestGetAs(t *testing.T) {
+	obj := Object{"id": 7, "name": "x"}
+
+	id, err := GetAs[int64](obj, "id")
+	require(t, err == nil && id == 7)
+	_, err = GetAs[int64](obj, "name")
+	require(t, err != nil)
+	_, err = GetAs[int64](obj, "missing")
+	require(t, errors.Is(err, ErrNotFound))
+}
+
+func TestMustDefault(t *testing.T) {
+	v := MustParse([]byte(`{"n":5,"s":"abc"}`))
+
+	require(t, Must[int](v.Path("n")) == 5)
+	require(t, call(func() { Must[int](v.Path("s")) }) != nil)
+	require(t, Default(v.Path("n"), 1) == 5)
+	require(t, Default(v.Path("s"), 1) == 1)
+	require(t, Default(v.Path("missing"), "def") == "def")
+}
@@ -41,7 +41,8 @@ func PostData(url string, jsonPostData any) (Value, error) {
 
 func RequestValue(method, url string, headers Object, body any) (val Value, err error) {
 	defer catch(&err)
-	return Parse(must(Request(method, url, headers, body)))
+	val, err = Parse(must(Request(method, url, headers, body)))
+	return val, withFile(err, url)
 }
 
 func Request(method, url string, headers Object, body any) (data []byte, err error) {
@@ -0,0 +1,1860 @@
+package js
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JQ is a compiled program in a subset of the jq language:
+// paths (`.a.b[0]`, `.[]`, `..`), pipes, commas, object and array
+// construction, arithmetic, comparisons, `and`/`or`/`//`, `if`,
+// `reduce`, `as $var` bindings, string interpolation and common builtins
+// (map, select, keys, to_entries, group_by, sort_by, add, del, ...).
+type JQ struct {
+	src  string
+	root jqNode
+}
+
+// CompileJQ parses a jq program.
+func CompileJQ(program string) (q *JQ, err error) {
+	defer catch(&err)
+	p := &jqParser{src: program}
+	root := p.parsePipe()
+	if p.skipWS(); p.pos < len(p.src) {
+		p.fail("unexpected `%s`", p.src[p.pos:])
+	}
+	return &JQ{program, root}, nil
+}
+
+// MustCompileJQ parses a jq program and panics on error.
+func MustCompileJQ(program string) *JQ {
+	return must(CompileJQ(program))
+}
+
+// String returns the source text of the program.
+func (q *JQ) String() string {
+	return q.src
+}
+
+// Run executes the program and returns all of its outputs.
+func (q *JQ) Run(v Value) (Array, error) {
+	return q.RunVars(v, nil)
+}
+
+// RunVars executes the program with predefined variables (`$name`).
+func (q *JQ) RunVars(v Value, vars Object) (_ Array, err error) {
+	defer catch(&err)
+	var env *jqEnv
+	for _, name := range vars.Keys() {
+		env = &jqEnv{name, jqNormalize(vars[name]), env}
+	}
+	res, err := q.root.eval(jqNormalize(v.val), env)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = Array{}
+	}
+	return Array(res), nil
+}
+
+// Transform executes the program and returns its first output
+// (or an empty Value if there is none).
+func (q *JQ) Transform(v Value) (Value, error) {
+	res, err := q.Run(v)
+	if err != nil || len(res) == 0 {
+		return Value{}, err
+	}
+	return Value{res[0]}, nil
+}
+
+// Transform compiles and executes a jq program against the value
+// and returns its first output.
+func (v Value) Transform(program string) (Value, error) {
+	q, err := CompileJQ(program)
+	if err != nil {
+		return Value{}, err
+	}
+	return q.Transform(v)
+}
+
+//--------- evaluation ----------
+
+type jqEnv struct {
+	name   string
+	val    any
+	parent *jqEnv
+}
+
+func (e *jqEnv) lookup(name string) (any, bool) {
+	for ; e != nil; e = e.parent {
+		if e.name == name {
+			return e.val, true
+		}
+	}
+	return nil, false
+}
+
+type jqNode interface {
+	eval(in any, env *jqEnv) ([]any, error)
+}
+
+type (
+	jqIdentity struct{}
+	jqRecurse  struct{}
+	jqLiteral  struct{ v any }
+	jqVar      struct{ name string }
+	jqField    struct {
+		target jqNode
+		name   string
+	}
+	jqIndex struct{ target, key jqNode }
+	jqSlice struct{ target, from, to jqNode }
+	jqIter  struct{ target jqNode }
+	jqTry   struct{ body jqNode }
+	jqPipe  struct{ l, r jqNode }
+	jqComma struct{ l, r jqNode }
+	jqAlt   struct{ l, r jqNode }
+	jqAnd   struct{ l, r jqNode }
+	jqOr    struct{ l, r jqNode }
+	jqNeg   struct{ x jqNode }
+	jqArray struct{ body jqNode }
+	jqBinOp struct {
+		op   string
+		l, r jqNode
+	}
+	jqString struct{ parts []jqNode } // literal parts are jqLiteral
+	jqObject struct{ entries []jqEntry }
+	jqEntry  struct{ key, val jqNode }
+	jqAs     struct {
+		source jqNode
+		name   string
+		body   jqNode
+	}
+	jqIf struct {
+		cond, then, els jqNode
+	}
+	jqReduce struct {
+		source       jqNode
+		name         string
+		init, update jqNode
+	}
+	jqCall struct {
+		name string
+		args []jqNode
+	}
+)
+
+func (jqIdentity) eval(in any, env *jqEnv) ([]any, error) {
+	return []any{in}, nil
+}
+
+func (jqRecurse) eval(in any, env *jqEnv) (out []any, err error) {
+	var walk func(v any)
+	walk = func(v any) {
+		out = append(out, v)
+		for _, c := range jqValues(v) {
+			walk(c)
+		}
+	}
+	walk(in)
+	return
+}
+
+func (x jqLiteral) eval(in any, env *jqEnv) ([]any, error) {
+	return []any{x.v}, nil
+}
+
+func (x jqVar) eval(in any, env *jqEnv) ([]any, error) {
+	v, ok := env.lookup(x.name)
+	if !ok {
+		return nil, fmt.Errorf("js: jq: $%s is not defined", x.name)
+	}
+	return []any{v}, nil
+}
+
+func (x jqField) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.target, in, env, func(v any) ([]any, error) {
+		r, err := jqIndexValue(v, x.name)
+		return []any{r}, err
+	})
+}
+
+func (x jqIndex) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.target, in, env, func(v any) ([]any, error) {
+		return jqMap(x.key, in, env, func(k any) ([]any, error) {
+			r, err := jqIndexValue(v, k)
+			return []any{r}, err
+		})
+	})
+}
+
+func (x jqSlice) eval(in any, env *jqEnv) ([]any, error) {
+	bound := func(n jqNode) ([]any, error) {
+		if n == nil {
+			return []any{nil}, nil
+		}
+		return n.eval(in, env)
+	}
+	return jqMap(x.target, in, env, func(v any) (out []any, err error) {
+		froms, err := bound(x.from)
+		if err != nil {
+			return nil, err
+		}
+		tos, err := bound(x.to)
+		if err != nil {
+			return nil, err
+		}
+		for _, from := range froms {
+			for _, to := range tos {
+				r, err := jqSliceValue(v, from, to)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, r)
+			}
+		}
+		return
+	})
+}
+
+func (x jqIter) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.target, in, env, func(v any) ([]any, error) {
+		switch typeName(v) {
+		case "array", "object":
+			return jqValues(v), nil
+		}
+		return nil, fmt.Errorf("js: jq: cannot iterate over %s", typeName(v))
+	})
+}
+
+func (x jqTry) eval(in any, env *jqEnv) ([]any, error) {
+	out, _ := x.body.eval(in, env)
+	return out, nil
+}
+
+func (x jqPipe) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.l, in, env, func(v any) ([]any, error) {
+		return x.r.eval(v, env)
+	})
+}
+
+func (x jqComma) eval(in any, env *jqEnv) ([]any, error) {
+	l, err := x.l.eval(in, env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := x.r.eval(in, env)
+	return append(l, r...), err
+}
+
+func (x jqAlt) eval(in any, env *jqEnv) ([]any, error) {
+	l, _ := x.l.eval(in, env)
+	var out []any
+	for _, v := range l {
+		if jqTruthy(v) {
+			out = append(out, v)
+		}
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return x.r.eval(in, env)
+}
+
+func (x jqAnd) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.l, in, env, func(l any) ([]any, error) {
+		if !jqTruthy(l) {
+			return []any{false}, nil
+		}
+		return jqMap(x.r, in, env, func(r any) ([]any, error) {
+			return []any{jqTruthy(r)}, nil
+		})
+	})
+}
+
+func (x jqOr) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.l, in, env, func(l any) ([]any, error) {
+		if jqTruthy(l) {
+			return []any{true}, nil
+		}
+		return jqMap(x.r, in, env, func(r any) ([]any, error) {
+			return []any{jqTruthy(r)}, nil
+		})
+	})
+}
+
+func (x jqNeg) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.x, in, env, func(v any) ([]any, error) {
+		if typeName(v) != "number" {
+			return nil, fmt.Errorf("js: jq: %s cannot be negated", typeName(v))
+		}
+		return []any{-ToNum(v)}, nil
+	})
+}
+
+func (x jqArray) eval(in any, env *jqEnv) ([]any, error) {
+	if x.body == nil {
+		return []any{Array{}}, nil
+	}
+	out, err := x.body.eval(in, env)
+	if out == nil {
+		out = []any{}
+	}
+	return []any{Array(out)}, err
+}
+
+func (x jqBinOp) eval(in any, env *jqEnv) ([]any, error) {
+	// jq evaluates the right operand in the outer loop
+	return jqMap(x.r, in, env, func(r any) ([]any, error) {
+		return jqMap(x.l, in, env, func(l any) ([]any, error) {
+			v, err := jqBinary(x.op, l, r)
+			return []any{v}, err
+		})
+	})
+}
+
+func (x jqString) eval(in any, env *jqEnv) ([]any, error) {
+	outs := []string{""}
+	for _, part := range x.parts {
+		if lit, ok := part.(jqLiteral); ok {
+			for i := range outs {
+				outs[i] += lit.v.(string)
+			}
+			continue
+		}
+		vals, err := part.eval(in, env)
+		if err != nil {
+			return nil, err
+		}
+		var next []string
+		for _, s := range outs {
+			for _, v := range vals {
+				next = append(next, s+jqToString(v))
+			}
+		}
+		outs = next
+	}
+	out := make([]any, len(outs))
+	for i, s := range outs {
+		out[i] = s
+	}
+	return out, nil
+}
+
+func (x jqObject) eval(in any, env *jqEnv) ([]any, error) {
+	objs := []Object{{}}
+	for _, e := range x.entries {
+		keys, err := e.key.eval(in, env)
+		if err != nil {
+			return nil, err
+		}
+		vals, err := e.val.eval(in, env)
+		if err != nil {
+			return nil, err
+		}
+		var next []Object
+		for _, obj := range objs {
+			for _, k := range keys {
+				key, ok := k.(string)
+				if !ok {
+					return nil, fmt.Errorf("js: jq: object keys must be strings, not %s", typeName(k))
+				}
+				for _, v := range vals {
+					next = append(next, obj.Clone().Set(key, v))
+				}
+			}
+		}
+		objs = next
+	}
+	out := make([]any, len(objs))
+	for i, obj := range objs {
+		out[i] = obj
+	}
+	return out, nil
+}
+
+func (x jqAs) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.source, in, env, func(v any) ([]any, error) {
+		return x.body.eval(in, &jqEnv{x.name, v, env})
+	})
+}
+
+func (x jqIf) eval(in any, env *jqEnv) ([]any, error) {
+	return jqMap(x.cond, in, env, func(c any) ([]any, error) {
+		if jqTruthy(c) {
+			return x.then.eval(in, env)
+		}
+		if x.els == nil {
+			return []any{in}, nil
+		}
+		return x.els.eval(in, env)
+	})
+}
+
+func (x jqReduce) eval(in any, env *jqEnv) ([]any, error) {
+	accs, err := x.init.eval(in, env)
+	if err != nil {
+		return nil, err
+	}
+	items, err := x.source.eval(in, env)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, acc := range accs {
+		for _, item := range items {
+			res, err := x.update.eval(acc, &jqEnv{x.name, item, env})
+			if err != nil {
+				return nil, err
+			}
+			if len(res) == 0 {
+				acc = nil
+			} else {
+				acc = res[len(res)-1]
+			}
+		}
+		out = append(out, acc)
+	}
+	return out, nil
+}
+
+// jqMap evaluates n and calls fn for each of its outputs, concatenating results.
+func jqMap(n jqNode, in any, env *jqEnv, fn func(any) ([]any, error)) ([]any, error) {
+	vals, err := n.eval(in, env)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, v := range vals {
+		res, err := fn(v)
+		if err != nil {
+			return out, err // keep the outputs produced before the error
+		}
+		out = append(out, res...)
+	}
+	return out, nil
+}
+
+// jqNormalize converts values that are not made of plain JSON types
+// (e.g. structs, typed slices or integers) through a JSON round-trip.
+func jqNormalize(v any) any {
+	var plain func(v any) bool
+	plain = func(v any) bool {
+		switch v := v.(type) {
+		case nil, bool, float64, string:
+			return true
+		case map[string]any:
+			return !slices.ContainsFunc(slices.Collect(maps.Values(v)), func(x any) bool { return !plain(x) })
+		case Object:
+			return plain(map[string]any(v))
+		case []any:
+			return !slices.ContainsFunc(v, func(x any) bool { return !plain(x) })
+		case Array:
+			return plain([]any(v))
+		}
+		return false
+	}
+	if plain(v) {
+		return v
+	}
+	var res any
+	json.Unmarshal(NewValue(v).Bytes(), &res) // ignore error
+	return res
+}
+
+func jqTruthy(v any) bool {
+	b, ok := v.(bool)
+	return !isNil(v) && (!ok || b)
+}
+
+// jqValues returns the elements of an array or the member values
+// of an object (in sorted key order).
+func jqValues(v any) []any {
+	return jpChildren(v)
+}
+
+func jqToString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return Encode(v)
+}
+
+func jqIndexValue(v, k any) (any, error) {
+	switch t := typeName(v); {
+	case t == "null":
+		return nil, nil
+	case t == "object" && typeName(k) == "string":
+		obj, _ := asObject(v)
+		return obj[k.(string)], nil
+	case t == "array" && typeName(k) == "number":
+		return Array(jqValues(v)).Eq(int(math.Floor(ToNum(k)))).val, nil
+	}
+	return nil, fmt.Errorf("js: jq: cannot index %s with %s", typeName(v), jqToString(k))
+}
+
+func jqSliceValue(v, from, to any) (any, error) {
+	var n int
+	switch typeName(v) {
+	case "null":
+		return nil, nil
+	case "array":
+		n = len(jqValues(v))
+	case "string":
+		n = utf8.RuneCountInString(v.(string))
+	default:
+		return nil, fmt.Errorf("js: jq: cannot slice %s", typeName(v))
+	}
+	idx := func(b any, def int) int {
+		if b == nil {
+			return def
+		}
+		// clamped before the conversion, which would overflow for huge bounds
+		i := int(min(max(math.Floor(ToNum(b)), -float64(n)), float64(n)))
+		if i < 0 {
+			i += n
+		}
+		return min(max(i, 0), n)
+	}
+	i, j := idx(from, 0), idx(to, n)
+	j = max(i, j)
+	if s, ok := v.(string); ok {
+		return string([]rune(s)[i:j]), nil
+	}
+	return slices.Clone(Array(jqValues(v))[i:j]), nil
+}
+
+// jqCompare orders values as jq does:
+// null < false < true < numbers < strings < arrays < objects.
+func jqCompare(a, b any) int {
+	rank := func(v any) int {
+		switch t := typeName(v); t {
+		case "boolean":
+			if v.(bool) {
+				return 2
+			}
+			return 1
+		default:
+			return map[string]int{"null": 0, "number": 3, "string": 4, "array": 5, "object": 6}[t]
+		}
+	}
+	ra, rb := rank(a), rank(b)
+	if ra != rb {
+		return _cmp(int64(ra), int64(rb))
+	}
+	switch ra {
+	case 3, 4:
+		return Cmp(a, b)
+	case 5:
+		aa, bb := jqValues(a), jqValues(b)
+		for i := 0; i < len(aa) && i < len(bb); i++ {
+			if c := jqCompare(aa[i], bb[i]); c != 0 {
+				return c
+			}
+		}
+		return _cmp(int64(len(aa)), int64(len(bb)))
+	case 6:
+		oa, _ := asObject(a)
+		ob, _ := asObject(b)
+		ka, kb := oa.Keys(), ob.Keys()
+		if c := jqCompare(ToArray(ka...), ToArray(kb...)); c != 0 {
+			return c
+		}
+		for _, k := range ka {
+			if c := jqCompare(oa[k], ob[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// jqASCIICase switches the case of the ASCII letters from lo to hi;
+// other characters are kept, as in ascii_downcase and ascii_upcase of jq.
+func jqASCIICase(s string, lo, hi byte) string {
+	b := []byte(s)
+	for i, c := range b {
+		if lo <= c && c <= hi {
+			b[i] = c ^ ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+// maxJQStringLen limits the length of strings built by `*`.
+const maxJQStringLen = 1 << 28
+
+func jqBinary(op string, l, r any) (any, error) {
+	lt, rt := typeName(l), typeName(r)
+	fail := func() (any, error) {
+		return nil, fmt.Errorf("js: jq: %s (%s) and %s (%s) cannot be used with `%s`", lt, jqToString(l), rt, jqToString(r), op)
+	}
+	switch op {
+	case "==":
+		return deepEqual(l, r), nil
+	case "!=":
+		return !deepEqual(l, r), nil
+	case "<":
+		return jqCompare(l, r) < 0, nil
+	case "<=":
+		return jqCompare(l, r) <= 0, nil
+	case ">":
+		return jqCompare(l, r) > 0, nil
+	case ">=":
+		return jqCompare(l, r) >= 0, nil
+	case "+":
+		switch {
+		case lt == "null":
+			return r, nil
+		case rt == "null":
+			return l, nil
+		case lt != rt:
+			return fail()
+		case lt == "number":
+			return ToNum(l) + ToNum(r), nil
+		case lt == "string":
+			return l.(string) + r.(string), nil
+		case lt == "array":
+			return append(slices.Clip(Array(jqValues(l))), jqValues(r)...), nil
+		case lt == "object":
+			lo, _ := asObject(l)
+			ro, _ := asObject(r)
+			return lo.Clone().Extend(ro), nil
+		}
+	case "-":
+		switch {
+		case lt != rt:
+		case lt == "number":
+			return ToNum(l) - ToNum(r), nil
+		case lt == "array":
+			rv := jqValues(r)
+			return Array(jqValues(l)).Filter(func(v Value) bool {
+				return !slices.ContainsFunc(rv, func(x any) bool { return deepEqual(v.val, x) })
+			}), nil
+		}
+	case "*":
+		switch {
+		case lt == "number" && rt == "number":
+			return ToNum(l) * ToNum(r), nil
+		case lt == "string" && rt == "number", lt == "number" && rt == "string":
+			s, n := l, r
+			if lt == "number" {
+				s, n = r, l
+			}
+			count := math.Ceil(ToNum(n))
+			if !(count > 0) {
+				return nil, nil
+			}
+			if str := s.(string); float64(len(str))*count <= maxJQStringLen {
+				return strings.Repeat(str, int(min(count, maxJQStringLen))), nil
+			}
+			return nil, fmt.Errorf("js: jq: repeat string result too long")
+		case lt == "object" && rt == "object":
+			return jqDeepMerge(l, r), nil
+		}
+	case "/":
+		switch {
+		case lt == "number" && rt == "number":
+			if ToNum(r) == 0 {
+				return nil, fmt.Errorf("js: jq: %s and %s cannot be divided because the divisor is zero", jqToString(l), jqToString(r))
+			}
+			return ToNum(l) / ToNum(r), nil
+		case lt == "string" && rt == "string":
+			return ToArray(strings.Split(l.(string), r.(string))...), nil
+		}
+	case "%":
+		if lt == "number" && rt == "number" {
+			a, b := int64(ToNum(l)), int64(ToNum(r))
+			if b == 0 {
+				return nil, fmt.Errorf("js: jq: %s and %s cannot be divided because the divisor is zero", jqToString(l), jqToString(r))
+			}
+			return float64(a % b), nil
+		}
+	}
+	return fail()
+}
+
+func jqDeepMerge(l, r any) any {
+	lo, _ := asObject(l)
+	ro, _ := asObject(r)
+	res := lo.Clone()
+	for k, v := range ro {
+		if typeName(res[k]) == "object" && typeName(v) == "object" {
+			res[k] = jqDeepMerge(res[k], v)
+		} else {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+//--------- builtins ----------
+
+type jqBuiltin func(in any, args []jqNode, env *jqEnv) ([]any, error)
+
+var jqBuiltins map[string]jqBuiltin // name/arity -> func
+
+func init() {
+	// simple functions of the input
+	fn0 := func(f func(in any) (any, error)) jqBuiltin {
+		return func(in any, _ []jqNode, _ *jqEnv) ([]any, error) {
+			v, err := f(in)
+			if err != nil {
+				return nil, err
+			}
+			return []any{v}, nil
+		}
+	}
+	// functions of the input and each output of a single argument
+	fn1 := func(f func(in, arg any) (any, error)) jqBuiltin {
+		return func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqMap(args[0], in, env, func(a any) ([]any, error) {
+				v, err := f(in, a)
+				return []any{v}, err
+			})
+		}
+	}
+	arr := func(name string, f func(Array) (any, error)) func(any) (any, error) {
+		return func(in any) (any, error) {
+			if typeName(in) != "array" {
+				return nil, fmt.Errorf("js: jq: %s: %s is not an array", name, typeName(in))
+			}
+			return f(Array(jqValues(in)))
+		}
+	}
+	str := func(name string, f func(s, arg string) any) func(any, any) (any, error) {
+		return func(in, arg any) (any, error) {
+			s, ok1 := in.(string)
+			a, ok2 := arg.(string)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("js: jq: %s: input and argument must be strings", name)
+			}
+			return f(s, a), nil
+		}
+	}
+	num := func(f func(float64) float64) jqBuiltin {
+		return fn0(func(in any) (any, error) {
+			if typeName(in) != "number" {
+				return nil, fmt.Errorf("js: jq: %s is not a number", typeName(in))
+			}
+			return f(ToNum(in)), nil
+		})
+	}
+	// keyed evaluates f for each element of the input array
+	keyed := func(in any, f jqNode, env *jqEnv) (items, keys []any, err error) {
+		if typeName(in) != "array" {
+			return nil, nil, fmt.Errorf("js: jq: %s is not an array", typeName(in))
+		}
+		items = jqValues(in)
+		keys = make([]any, len(items))
+		for i, v := range items {
+			k, err := f.eval(v, env)
+			if err != nil {
+				return nil, nil, err
+			}
+			keys[i] = Array(k)
+		}
+		return
+	}
+	sortedBy := func(in any, f jqNode, env *jqEnv) (items, keys []any, err error) {
+		if items, keys, err = keyed(in, f, env); err == nil {
+			idx := make([]int, len(items))
+			for i := range idx {
+				idx[i] = i
+			}
+			sort.SliceStable(idx, func(i, j int) bool { return jqCompare(keys[idx[i]], keys[idx[j]]) < 0 })
+			si, sk := make([]any, len(idx)), make([]any, len(idx))
+			for i, j := range idx {
+				si[i], sk[i] = items[j], keys[j]
+			}
+			items, keys = si, sk
+		}
+		return
+	}
+	identity := jqIdentity{}
+
+	jqBuiltins = map[string]jqBuiltin{
+		"empty/0": func(any, []jqNode, *jqEnv) ([]any, error) { return nil, nil },
+		"not/0":   fn0(func(in any) (any, error) { return !jqTruthy(in), nil }),
+		"error/0": func(in any, _ []jqNode, _ *jqEnv) ([]any, error) {
+			return nil, fmt.Errorf("js: jq: %s", jqToString(in))
+		},
+		"error/1": fn1(func(_, msg any) (any, error) { return nil, fmt.Errorf("js: jq: %s", jqToString(msg)) }),
+		"type/0":  fn0(func(in any) (any, error) { return typeName(in), nil }),
+		"length/0": fn0(func(in any) (any, error) {
+			switch t := typeName(in); t {
+			case "null":
+				return 0.0, nil
+			case "number":
+				return math.Abs(ToNum(in)), nil
+			case "string":
+				return float64(utf8.RuneCountInString(in.(string))), nil
+			case "array", "object":
+				return float64(len(jqValues(in))), nil
+			default:
+				return nil, fmt.Errorf("js: jq: %s has no length", t)
+			}
+		}),
+		"keys/0": fn0(func(in any) (any, error) {
+			switch typeName(in) {
+			case "object":
+				obj, _ := asObject(in)
+				return ToArray(obj.Keys()...), nil
+			case "array":
+				keys := Array{}
+				for i := range jqValues(in) {
+					keys = append(keys, float64(i))
+				}
+				return keys, nil
+			}
+			return nil, fmt.Errorf("js: jq: %s has no keys", typeName(in))
+		}),
+		"has/1": fn1(func(in, k any) (any, error) {
+			switch {
+			case typeName(in) == "object" && typeName(k) == "string":
+				obj, _ := asObject(in)
+				return obj.Has(k.(string)), nil
+			case typeName(in) == "array" && typeName(k) == "number":
+				i := ToNum(k)
+				return i >= 0 && i < float64(len(jqValues(in))), nil
+			}
+			return nil, fmt.Errorf("js: jq: cannot check whether %s has a %s key", typeName(in), typeName(k))
+		}),
+		"map/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqArray{jqPipe{jqIter{identity}, args[0]}}.eval(in, env)
+		},
+		"select/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqMap(args[0], in, env, func(c any) ([]any, error) {
+				if jqTruthy(c) {
+					return []any{in}, nil
+				}
+				return nil, nil
+			})
+		},
+		"to_entries/0": fn0(func(in any) (any, error) {
+			obj, ok := asObject(in)
+			if !ok {
+				return nil, fmt.Errorf("js: jq: to_entries: %s is not an object", typeName(in))
+			}
+			entries := Array{}
+			for _, k := range obj.Keys() {
+				entries = append(entries, Object{"key": k, "value": obj[k]})
+			}
+			return entries, nil
+		}),
+		"from_entries/0": fn0(arr("from_entries", func(a Array) (any, error) {
+			obj := Object{}
+			for _, e := range a.Objects() {
+				k := e.GetNoNil("key", "k", "name", "Name", "Key", "K").val
+				switch k.(type) {
+				case string:
+				case bool, float64, int:
+					k = jqToString(k)
+				default:
+					return nil, fmt.Errorf("js: jq: from_entries: invalid key %s", jqToString(k))
+				}
+				v := e.GetNoNil("value", "v", "Value").val
+				obj[k.(string)] = v
+			}
+			return obj, nil
+		})),
+		"with_entries/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqPipe{jqCall{"to_entries", nil}, jqPipe{jqCall{"map", args}, jqCall{"from_entries", nil}}}.eval(in, env)
+		},
+		"add/0": fn0(arr("add", func(a Array) (acc any, err error) {
+			for _, v := range a {
+				if acc, err = jqBinary("+", acc, v); err != nil {
+					return nil, err
+				}
+			}
+			return
+		})),
+		"any/0": fn0(arr("any", func(a Array) (any, error) {
+			return slices.ContainsFunc(a, jqTruthy), nil
+		})),
+		"all/0": fn0(arr("all", func(a Array) (any, error) {
+			return !slices.ContainsFunc(a, func(v any) bool { return !jqTruthy(v) }), nil
+		})),
+		"any/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqPipe{jqCall{"map", args}, jqCall{"any", nil}}.eval(in, env)
+		},
+		"all/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqPipe{jqCall{"map", args}, jqCall{"all", nil}}.eval(in, env)
+		},
+		"range/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqMap(args[0], in, env, func(n any) (out []any, _ error) {
+				for i := 0.0; i < ToNum(n); i++ {
+					out = append(out, i)
+				}
+				return
+			})
+		},
+		"range/2": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			return jqMap(args[0], in, env, func(from any) ([]any, error) {
+				return jqMap(args[1], in, env, func(to any) (out []any, _ error) {
+					for i := ToNum(from); i < ToNum(to); i++ {
+						out = append(out, i)
+					}
+					return
+				})
+			})
+		},
+		"sort/0": fn0(arr("sort", func(a Array) (any, error) {
+			a = slices.Clone(a)
+			sort.SliceStable(a, func(i, j int) bool { return jqCompare(a[i], a[j]) < 0 })
+			return a, nil
+		})),
+		"sort_by/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			items, _, err := sortedBy(in, args[0], env)
+			if items == nil {
+				items = []any{}
+			}
+			return []any{Array(items)}, err
+		},
+		"group_by/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			items, keys, err := sortedBy(in, args[0], env)
+			groups := Array{}
+			for i := range items {
+				if i == 0 || jqCompare(keys[i-1], keys[i]) != 0 {
+					groups = append(groups, Array{})
+				}
+				groups[len(groups)-1] = append(groups[len(groups)-1].(Array), items[i])
+			}
+			return []any{groups}, err
+		},
+		"unique_by/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			items, keys, err := sortedBy(in, args[0], env)
+			res := Array{}
+			for i := range items {
+				if i == 0 || jqCompare(keys[i-1], keys[i]) != 0 {
+					res = append(res, items[i])
+				}
+			}
+			return []any{res}, err
+		},
+		"min_by/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			items, _, err := sortedBy(in, args[0], env)
+			return []any{Array(items).First().val}, err
+		},
+		"max_by/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			items, _, err := sortedBy(in, args[0], env)
+			return []any{Array(items).Last().val}, err
+		},
+		"unique/0": func(in any, _ []jqNode, env *jqEnv) ([]any, error) {
+			return jqCall{"unique_by", []jqNode{identity}}.eval(in, env)
+		},
+		"min/0": func(in any, _ []jqNode, env *jqEnv) ([]any, error) {
+			return jqCall{"min_by", []jqNode{identity}}.eval(in, env)
+		},
+		"max/0": func(in any, _ []jqNode, env *jqEnv) ([]any, error) {
+			return jqCall{"max_by", []jqNode{identity}}.eval(in, env)
+		},
+		"reverse/0": fn0(func(in any) (any, error) {
+			if s, ok := in.(string); ok {
+				r := []rune(s)
+				slices.Reverse(r)
+				return string(r), nil
+			}
+			if isNil(in) {
+				return Array{}, nil
+			}
+			return arr("reverse", func(a Array) (any, error) {
+				a = slices.Clone(a)
+				a.Reverse()
+				return a, nil
+			})(in)
+		}),
+		"flatten/0": fn0(arr("flatten", func(a Array) (any, error) {
+			return jqFlatten(a, 1e9), nil
+		})),
+		"flatten/1": fn1(func(in, depth any) (any, error) {
+			if ToNum(depth) < 0 {
+				return nil, fmt.Errorf("js: jq: flatten depth must not be negative")
+			}
+			return arr("flatten", func(a Array) (any, error) {
+				return jqFlatten(a, int(ToNum(depth))), nil
+			})(in)
+		}),
+		"first/0": fn0(func(in any) (any, error) { return jqIndexValue(in, 0.0) }),
+		"last/0":  fn0(func(in any) (any, error) { return jqIndexValue(in, -1.0) }),
+		"first/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			out, err := args[0].eval(in, env)
+			return out[:min(len(out), 1)], err
+		},
+		"last/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			out, err := args[0].eval(in, env)
+			return out[max(len(out)-1, 0):], err
+		},
+		"values/0": func(in any, _ []jqNode, _ *jqEnv) ([]any, error) {
+			if isNil(in) {
+				return nil, nil
+			}
+			return []any{in}, nil
+		},
+		"join/1": fn1(func(in, sep any) (any, error) {
+			s, ok := sep.(string)
+			if !ok || typeName(in) != "array" {
+				return nil, fmt.Errorf("js: jq: join: input must be an array and separator a string")
+			}
+			var parts []string
+			for _, v := range jqValues(in) {
+				switch typeName(v) {
+				case "null":
+					parts = append(parts, "")
+				case "array", "object":
+					return nil, fmt.Errorf("js: jq: join: cannot join %s", typeName(v))
+				default:
+					parts = append(parts, jqToString(v))
+				}
+			}
+			return strings.Join(parts, s), nil
+		}),
+		"split/1": fn1(str("split", func(s, sep string) any {
+			return ToArray(strings.Split(s, sep)...)
+		})),
+		"ltrimstr/1": fn1(func(in, pre any) (any, error) {
+			s, ok1 := in.(string)
+			p, ok2 := pre.(string)
+			if ok1 && ok2 {
+				return strings.TrimPrefix(s, p), nil
+			}
+			return in, nil
+		}),
+		"rtrimstr/1": fn1(func(in, suf any) (any, error) {
+			s, ok1 := in.(string)
+			p, ok2 := suf.(string)
+			if ok1 && ok2 {
+				return strings.TrimSuffix(s, p), nil
+			}
+			return in, nil
+		}),
+		"startswith/1": fn1(str("startswith", func(s, p string) any { return strings.HasPrefix(s, p) })),
+		"endswith/1":   fn1(str("endswith", func(s, p string) any { return strings.HasSuffix(s, p) })),
+		"test/1": fn1(func(in, re any) (any, error) {
+			s, ok1 := in.(string)
+			p, ok2 := re.(string)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("js: jq: test: input and regex must be strings")
+			}
+			rx, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("js: jq: test: %w", err)
+			}
+			return rx.MatchString(s), nil
+		}),
+		"contains/1": fn1(func(in, b any) (any, error) {
+			if typeName(in) != typeName(b) {
+				return nil, fmt.Errorf("js: jq: %s and %s cannot have their containment checked", typeName(in), typeName(b))
+			}
+			return jqContains(in, b), nil
+		}),
+		"ascii_downcase/0": fn0(func(in any) (any, error) {
+			if s, ok := in.(string); ok {
+				return jqASCIICase(s, 'A', 'Z'), nil
+			}
+			return nil, fmt.Errorf("js: jq: ascii_downcase: %s is not a string", typeName(in))
+		}),
+		"ascii_upcase/0": fn0(func(in any) (any, error) {
+			if s, ok := in.(string); ok {
+				return jqASCIICase(s, 'a', 'z'), nil
+			}
+			return nil, fmt.Errorf("js: jq: ascii_upcase: %s is not a string", typeName(in))
+		}),
+		"tostring/0": fn0(func(in any) (any, error) { return jqToString(in), nil }),
+		"tonumber/0": fn0(func(in any) (any, error) {
+			if typeName(in) == "number" {
+				return in, nil
+			}
+			s, _ := in.(string)
+			f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil {
+				return nil, fmt.Errorf("js: jq: cannot parse %s as number", jqToString(in))
+			}
+			return f, nil
+		}),
+		"tojson/0": fn0(func(in any) (any, error) { return Encode(in), nil }),
+		"fromjson/0": fn0(func(in any) (any, error) {
+			s, ok := in.(string)
+			if !ok {
+				return nil, fmt.Errorf("js: jq: fromjson: %s is not a string", typeName(in))
+			}
+			var v any
+			err := json.Unmarshal([]byte(s), &v)
+			return v, err
+		}),
+		"floor/0": num(math.Floor),
+		"ceil/0":  num(math.Ceil),
+		"round/0": num(math.Round),
+		"sqrt/0":  num(math.Sqrt),
+		"fabs/0":  num(math.Abs),
+		"del/1": func(in any, args []jqNode, env *jqEnv) ([]any, error) {
+			paths, err := jqPaths(args[0], in, env)
+			if err != nil {
+				return nil, err
+			}
+			// delete from the end so that array indexes stay valid
+			sort.SliceStable(paths, func(i, j int) bool { return jqCompare(paths[i], paths[j]) > 0 })
+			out := in
+			for _, p := range paths {
+				if out, err = jqDelPath(out, p); err != nil {
+					return nil, err
+				}
+			}
+			return []any{out}, nil
+		},
+		"paths/0": func(in any, _ []jqNode, env *jqEnv) ([]any, error) {
+			paths, err := jqPaths(jqRecurse{}, in, env)
+			if len(paths) > 0 {
+				paths = paths[1:] // skip the empty path of the input itself
+			}
+			out := make([]any, len(paths))
+			for i, p := range paths {
+				out[i] = p
+			}
+			return out, err
+		},
+		"getpath/1": fn1(func(in, p any) (any, error) {
+			if typeName(p) != "array" {
+				return nil, fmt.Errorf("js: jq: getpath: path must be an array")
+			}
+			v := in
+			for _, k := range jqValues(p) {
+				var err error
+				if v, err = jqIndexValue(v, k); err != nil {
+					return nil, err
+				}
+			}
+			return v, nil
+		}),
+	}
+	jqBuiltins["keys_unsorted/0"] = jqBuiltins["keys/0"]
+}
+
+func (x jqCall) eval(in any, env *jqEnv) ([]any, error) {
+	return jqBuiltins[x.name+"/"+strconv.Itoa(len(x.args))](in, x.args, env)
+}
+
+func jqFlatten(a Array, depth int) Array {
+	res := Array{}
+	for _, v := range a {
+		if typeName(v) == "array" && depth > 0 {
+			res = append(res, jqFlatten(jqValues(v), depth-1)...)
+		} else {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func jqContains(a, b any) bool {
+	switch typeName(a) {
+	case "object":
+		oa, _ := asObject(a)
+		ob, _ := asObject(b)
+		for k, v := range ob {
+			if w, ok := oa[k]; !ok || typeName(v) != typeName(w) || !jqContains(w, v) {
+				return false
+			}
+		}
+		return true
+	case "array":
+		for _, v := range jqValues(b) {
+			if !slices.ContainsFunc(jqValues(a), func(w any) bool {
+				return typeName(v) == typeName(w) && jqContains(w, v)
+			}) {
+				return false
+			}
+		}
+		return true
+	case "string":
+		return strings.Contains(a.(string), b.(string))
+	}
+	return deepEqual(a, b)
+}
+
+// jqPaths evaluates a path expression, returning the paths (arrays of
+// keys and indexes) it refers to inside the input.
+func jqPaths(n jqNode, in any, env *jqEnv) (paths []Array, err error) {
+	type item struct {
+		path Array
+		val  any
+	}
+	var walk func(n jqNode, cur []item) ([]item, error)
+	walk = func(n jqNode, cur []item) (out []item, err error) {
+		step := func(fn func(it item) ([]item, error)) ([]item, error) {
+			for _, it := range cur {
+				res, err := fn(it)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, res...)
+			}
+			return out, nil
+		}
+		child := func(it item, k any) (item, error) {
+			v, err := jqIndexValue(it.val, k)
+			return item{append(slices.Clip(it.path), k), v}, err
+		}
+		switch x := n.(type) {
+		case jqIdentity:
+			return cur, nil
+		case jqRecurse:
+			return step(func(it item) (res []item, err error) {
+				var rec func(it item) error
+				rec = func(it item) error {
+					res = append(res, it)
+					for _, k := range jqKeys(it.val) {
+						c, err := child(it, k)
+						if err != nil {
+							return err
+						}
+						if err = rec(c); err != nil {
+							return err
+						}
+					}
+					return nil
+				}
+				err = rec(it)
+				return
+			})
+		case jqField:
+			if cur, err = walk(x.target, cur); err != nil {
+				return nil, err
+			}
+			return step(func(it item) ([]item, error) {
+				c, err := child(it, x.name)
+				return []item{c}, err
+			})
+		case jqIndex:
+			if cur, err = walk(x.target, cur); err != nil {
+				return nil, err
+			}
+			return step(func(it item) (res []item, err error) {
+				keys, err := x.key.eval(in, env)
+				for _, k := range keys {
+					c, err := child(it, k)
+					if err != nil {
+						return nil, err
+					}
+					res = append(res, c)
+				}
+				return res, err
+			})
+		case jqIter:
+			if cur, err = walk(x.target, cur); err != nil {
+				return nil, err
+			}
+			return step(func(it item) (res []item, err error) {
+				if t := typeName(it.val); t != "array" && t != "object" {
+					return nil, fmt.Errorf("js: jq: cannot iterate over %s", t)
+				}
+				for _, k := range jqKeys(it.val) {
+					c, _ := child(it, k)
+					res = append(res, c)
+				}
+				return
+			})
+		case jqTry:
+			res, _ := walk(x.body, cur)
+			return res, nil
+		case jqPipe:
+			if cur, err = walk(x.l, cur); err != nil {
+				return nil, err
+			}
+			return walk(x.r, cur)
+		case jqComma:
+			l, err := walk(x.l, cur)
+			if err != nil {
+				return nil, err
+			}
+			r, err := walk(x.r, cur)
+			return append(l, r...), err
+		case jqIf:
+			return step(func(it item) (res []item, err error) {
+				conds, err := x.cond.eval(it.val, env)
+				for _, c := range conds {
+					branch := x.els
+					if jqTruthy(c) {
+						branch = x.then
+					}
+					if branch == nil {
+						branch = jqIdentity{}
+					}
+					r, err := walk(branch, []item{it})
+					if err != nil {
+						return nil, err
+					}
+					res = append(res, r...)
+				}
+				return res, err
+			})
+		case jqCall:
+			switch x.name + "/" + strconv.Itoa(len(x.args)) {
+			case "select/1":
+				return step(func(it item) (res []item, err error) {
+					conds, err := x.args[0].eval(it.val, env)
+					for _, c := range conds {
+						if jqTruthy(c) {
+							res = append(res, it)
+						}
+					}
+					return res, err
+				})
+			case "empty/0":
+				return nil, nil
+			case "first/0", "last/0":
+				return step(func(it item) ([]item, error) {
+					c, err := child(it, map[string]any{"first": 0.0, "last": -1.0}[x.name])
+					return []item{c}, err
+				})
+			}
+		}
+		return nil, fmt.Errorf("js: jq: invalid path expression")
+	}
+	items, err := walk(n, []item{{Array{}, in}})
+	for _, it := range items {
+		paths = append(paths, it.path)
+	}
+	return
+}
+
+// jqKeys returns the keys (or indexes) of an object or array.
+func jqKeys(v any) (keys []any) {
+	switch typeName(v) {
+	case "object":
+		obj, _ := asObject(v)
+		for _, k := range obj.Keys() {
+			keys = append(keys, k)
+		}
+	case "array":
+		for i := range jqValues(v) {
+			keys = append(keys, float64(i))
+		}
+	}
+	return
+}
+
+// jqDelPath returns a copy of v with the value at path removed;
+// containers along the path are copied, the input is not modified.
+func jqDelPath(v any, path Array) (any, error) {
+	if len(path) == 0 || isNil(v) {
+		return nil, nil
+	}
+	k := path[0]
+	switch typeName(v) {
+	case "object":
+		key, ok := k.(string)
+		if !ok {
+			break
+		}
+		obj, _ := asObject(v)
+		res := obj.Clone()
+		if len(path) == 1 {
+			delete(res, key)
+			return res, nil
+		}
+		if _, ok := res[key]; ok {
+			c, err := jqDelPath(res[key], path[1:])
+			res[key] = c
+			return res, err
+		}
+		return res, nil
+	case "array":
+		if typeName(k) != "number" {
+			break
+		}
+		arr := slices.Clone(Array(jqValues(v)))
+		i := int(ToNum(k))
+		if i < 0 {
+			i += len(arr)
+		}
+		if i < 0 || i >= len(arr) {
+			return arr, nil
+		}
+		if len(path) == 1 {
+			return slices.Delete(arr, i, i+1), nil
+		}
+		c, err := jqDelPath(arr[i], path[1:])
+		arr[i] = c
+		return arr, err
+	}
+	return nil, fmt.Errorf("js: jq: cannot delete field at %s of %s", jqToString(k), typeName(v))
+}
+
+//--------- parser ----------
+
+type jqParser struct {
+	src string
+	pos int
+}
+
+var jqKeywords = map[string]bool{
+	"if": true, "then": true, "elif": true, "else": true, "end": true, "as": true,
+	"reduce": true, "and": true, "or": true, "def": true, "try": true, "catch": true,
+}
+
+func (p *jqParser) fail(format string, args ...any) {
+	panic(fmt.Errorf("js: invalid jq program at offset %d: %s", p.pos, fmt.Sprintf(format, args...)))
+}
+
+func (p *jqParser) skipWS() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '#': // comment to the end of line
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *jqParser) peek() byte {
+	p.skipWS()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// eat consumes the operator or punctuation s if it comes next.
+func (p *jqParser) eat(s string) bool {
+	p.skipWS()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *jqParser) expect(s string) {
+	if !p.eat(s) {
+		p.fail("expected `%s`", s)
+	}
+}
+
+// keyword consumes the identifier kw if it comes next.
+func (p *jqParser) keyword(kw string) bool {
+	save := p.pos
+	if p.ident() == kw {
+		return true
+	}
+	p.pos = save
+	return false
+}
+
+func (p *jqParser) expectKeyword(kw string) {
+	if !p.keyword(kw) {
+		p.fail("expected `%s`", kw)
+	}
+}
+
+func (p *jqParser) ident() string {
+	p.skipWS()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !(c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || p.pos > start && '0' <= c && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *jqParser) varName() string {
+	p.expect("$")
+	name := p.ident()
+	if name == "" {
+		p.fail("variable name expected")
+	}
+	return name
+}
+
+// parsePipe: `a | b`, `term as $x | b`; lowest precedence.
+func (p *jqParser) parsePipe() jqNode {
+	l := p.parseComma()
+	if p.peek() == '|' && !strings.HasPrefix(p.src[p.pos:], "|=") {
+		p.pos++
+		return jqPipe{l, p.parsePipe()}
+	}
+	return l
+}
+
+func (p *jqParser) parseComma() jqNode {
+	l := p.parseAlt()
+	for p.eat(",") {
+		l = jqComma{l, p.parseAlt()}
+	}
+	return l
+}
+
+// parseAlt: `a // b`, right-associative.
+func (p *jqParser) parseAlt() jqNode {
+	l := p.parseOr()
+	if p.eat("//") {
+		return jqAlt{l, p.parseAlt()}
+	}
+	return l
+}
+
+func (p *jqParser) parseOr() jqNode {
+	l := p.parseAnd()
+	for p.keyword("or") {
+		l = jqOr{l, p.parseAnd()}
+	}
+	return l
+}
+
+func (p *jqParser) parseAnd() jqNode {
+	l := p.parseCmp()
+	for p.keyword("and") {
+		l = jqAnd{l, p.parseCmp()}
+	}
+	return l
+}
+
+func (p *jqParser) parseCmp() jqNode {
+	l := p.parseAdd()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.eat(op) {
+			return jqBinOp{op, l, p.parseAdd()}
+		}
+	}
+	return l
+}
+
+func (p *jqParser) parseAdd() jqNode {
+	l := p.parseMul()
+	for {
+		if p.peek() == '+' || p.peek() == '-' {
+			op := p.src[p.pos : p.pos+1]
+			p.pos++
+			l = jqBinOp{op, l, p.parseMul()}
+		} else {
+			return l
+		}
+	}
+}
+
+func (p *jqParser) parseMul() jqNode {
+	l := p.parseUnary()
+	for {
+		c := p.peek()
+		if (c == '*' || c == '%' || c == '/') && !strings.HasPrefix(p.src[p.pos:], "//") {
+			p.pos++
+			l = jqBinOp{string(c), l, p.parseUnary()}
+		} else {
+			return l
+		}
+	}
+}
+
+func (p *jqParser) parseUnary() jqNode {
+	if p.eat("-") {
+		return jqNeg{p.parsePostfix()}
+	}
+	t := p.parsePostfix()
+	if p.keyword("as") {
+		// `term as $x | body` binds tighter than the operators around it,
+		// and the body extends to the end of the enclosing pipe, as in jq:
+		// `1, 2 as $x | $x` is `1, (2 as $x | $x)`
+		name := p.varName()
+		p.expect("|")
+		return jqAs{t, name, p.parsePipe()}
+	}
+	return t
+}
+
+func (p *jqParser) parsePostfix() jqNode {
+	t := p.parseTerm()
+	for {
+		switch {
+		case p.eat("?"):
+			t = jqTry{t}
+		case p.peek() == '[':
+			t = p.parseBracket(t)
+		case p.peek() == '.' && p.pos+1 < len(p.src) && p.src[p.pos+1] != '.':
+			p.pos++
+			if p.peek() == '[' {
+				t = p.parseBracket(t)
+			} else {
+				t = p.parseFieldName(t)
+			}
+		default:
+			return t
+		}
+	}
+}
+
+// parseFieldName parses the name after `.`: an identifier or a string.
+func (p *jqParser) parseFieldName(target jqNode) jqNode {
+	if p.pos < len(p.src) && p.src[p.pos] == '"' {
+		s := p.parseString()
+		if lit, ok := s.(jqLiteral); ok {
+			return jqField{target, lit.v.(string)}
+		}
+		return jqIndex{target, s}
+	}
+	name := p.ident()
+	if name == "" {
+		p.fail("field name expected")
+	}
+	return jqField{target, name}
+}
+
+func (p *jqParser) parseBracket(target jqNode) jqNode {
+	p.expect("[")
+	if p.eat("]") {
+		return jqIter{target}
+	}
+	var from jqNode
+	if !p.eat(":") {
+		from = p.parsePipe()
+		if !p.eat(":") {
+			p.expect("]")
+			return jqIndex{target, from}
+		}
+	}
+	if p.eat("]") {
+		if from == nil {
+			p.fail("slice bound expected")
+		}
+		return jqSlice{target, from, nil}
+	}
+	to := p.parsePipe()
+	p.expect("]")
+	return jqSlice{target, from, to}
+}
+
+func (p *jqParser) parseTerm() jqNode {
+	switch c := p.peek(); {
+	case c == 0:
+		p.fail("unexpected end of program")
+	case c == '.':
+		p.pos++
+		if p.pos < len(p.src) && p.src[p.pos] == '.' {
+			p.pos++
+			return jqRecurse{}
+		}
+		if p.pos < len(p.src) {
+			switch c := p.src[p.pos]; {
+			case c == '[':
+				return p.parseBracket(jqIdentity{})
+			case c == '"' || c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+				return p.parseFieldName(jqIdentity{})
+			}
+		}
+		return jqIdentity{}
+	case c == '"':
+		return p.parseString()
+	case '0' <= c && c <= '9':
+		return jqLiteral{p.parseNumber()}
+	case c == '(':
+		p.pos++
+		x := p.parsePipe()
+		p.expect(")")
+		return x
+	case c == '[':
+		p.pos++
+		if p.eat("]") {
+			return jqArray{}
+		}
+		x := p.parsePipe()
+		p.expect("]")
+		return jqArray{x}
+	case c == '{':
+		return p.parseObject()
+	case c == '$':
+		return jqVar{p.varName()}
+	}
+	start := p.pos
+	name := p.ident()
+	switch name {
+	case "":
+		p.fail("unexpected `%c`", p.src[p.pos])
+	case "true":
+		return jqLiteral{true}
+	case "false":
+		return jqLiteral{false}
+	case "null":
+		return jqLiteral{nil}
+	case "if":
+		return p.parseIf()
+	case "reduce":
+		source := p.parsePostfix()
+		p.expectKeyword("as")
+		name := p.varName()
+		p.expect("(")
+		init := p.parsePipe()
+		p.expect(";")
+		update := p.parsePipe()
+		p.expect(")")
+		return jqReduce{source, name, init, update}
+	}
+	if jqKeywords[name] {
+		p.pos = start
+		p.fail("unexpected keyword `%s`", name)
+	}
+	var args []jqNode
+	if p.eat("(") {
+		for {
+			args = append(args, p.parsePipe())
+			if p.eat(")") {
+				break
+			}
+			p.expect(";")
+		}
+	}
+	if _, ok := jqBuiltins[name+"/"+strconv.Itoa(len(args))]; !ok {
+		p.pos = start
+		p.fail("%s/%d is not defined", name, len(args))
+	}
+	return jqCall{name, args}
+}
+
+func (p *jqParser) parseIf() jqNode {
+	cond := p.parsePipe()
+	p.expectKeyword("then")
+	x := jqIf{cond: cond, then: p.parsePipe()}
+	switch {
+	case p.keyword("elif"):
+		x.els = p.parseIf()
+		return x
+	case p.keyword("else"):
+		x.els = p.parsePipe()
+	}
+	p.expectKeyword("end")
+	return x
+}
+
+func (p *jqParser) parseObject() jqNode {
+	p.expect("{")
+	var obj jqObject
+	if p.eat("}") {
+		return obj
+	}
+	for {
+		var e jqEntry
+		switch c := p.peek(); {
+		case c == '$':
+			name := p.varName()
+			e = jqEntry{jqLiteral{name}, jqVar{name}}
+		case c == '"':
+			e.key = p.parseString()
+		case c == '(':
+			p.pos++
+			e.key = p.parsePipe()
+			p.expect(")")
+		default:
+			name := p.ident()
+			if name == "" {
+				p.fail("object key expected")
+			}
+			e.key = jqLiteral{name}
+		}
+		if e.val == nil {
+			if p.eat(":") {
+				e.val = p.parseObjectValue()
+			} else if lit, ok := e.key.(jqLiteral); ok {
+				e.val = jqField{jqIdentity{}, lit.v.(string)} // {a} is {a: .a}
+			} else {
+				p.fail("`:` expected")
+			}
+		}
+		obj.entries = append(obj.entries, e)
+		if p.eat("}") {
+			return obj
+		}
+		p.expect(",")
+	}
+}
+
+// parseObjectValue parses an object value: a pipe without top-level commas.
+func (p *jqParser) parseObjectValue() jqNode {
+	l := p.parseAlt()
+	if p.eat("|") {
+		return jqPipe{l, p.parseObjectValue()}
+	}
+	return l
+}
+
+func (p *jqParser) parseNumber() float64 {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte("0123456789.eE", p.src[p.pos]) >= 0 {
+		if c := p.src[p.pos]; (c == 'e' || c == 'E') && p.pos+1 < len(p.src) && (p.src[p.pos+1] == '-' || p.src[p.pos+1] == '+') {
+			p.pos++
+		}
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		p.fail("invalid number `%s`", p.src[start:p.pos])
+	}
+	return f
+}
+
+// parseString parses a string literal with `\(expr)` interpolation.
+func (p *jqParser) parseString() jqNode {
+	p.expect(`"`)
+	var parts []jqNode
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			p.fail("unterminated string")
+		}
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			if len(parts) == 0 {
+				return jqLiteral{sb.String()}
+			}
+			parts = append(parts, jqLiteral{sb.String()})
+			return jqString{parts}
+		case '\\':
+			if p.pos >= len(p.src) {
+				p.fail("unterminated string")
+			}
+			e := p.src[p.pos]
+			p.pos++
+			switch e {
+			case '(':
+				parts = append(parts, jqLiteral{sb.String()})
+				sb.Reset()
+				parts = append(parts, p.parsePipe())
+				p.expect(")")
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					p.fail("invalid unicode escape")
+				}
+				s, err := strconv.Unquote(`"\u` + p.src[p.pos:p.pos+4] + `"`)
+				if err != nil {
+					p.fail("invalid unicode escape")
+				}
+				sb.WriteString(s)
+				p.pos += 4
+			default:
+				r, ok := map[byte]byte{'n': '\n', 't': '\t', 'r': '\r', 'b': '\b', 'f': '\f', '"': '"', '\\': '\\', '/': '/'}[e]
+				if !ok {
+					p.fail("invalid escape `\\%c`", e)
+				}
+				sb.WriteByte(r)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package js
+
+import "testing"
+
+func jq(v Value, program string) string {
+	res, err := MustCompileJQ(program).Run(v)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return res.String()
+}
+
+var testOrders = MustParse([]byte(`{"orders":[
+	{"id":1,"user":"bob","total":10,"tags":["a"]},
+	{"id":2,"user":"alice","total":25.5,"tags":[]},
+	{"id":3,"user":"bob","total":4,"tags":["b","c"]}
+]}`))
+
+func TestJQ_paths(t *testing.T) {
+	require(t, jq(testOrders, `.orders[0].user`) == `["bob"]`)
+	require(t, jq(testOrders, `.orders[-1].id`) == `[3]`)
+	require(t, jq(testOrders, `.orders[].id`) == `[1,2,3]`)
+	require(t, jq(testOrders, `.orders[1:][].id`) == `[2,3]`)
+	require(t, jq(Value{}, `[1,2] | .[1e300:], .[:-1e300], .[-1e300:1e300]`) == `[[],[],[1,2]]`)
+	require(t, jq(testOrders, `.orders | length`) == `[3]`)
+	require(t, jq(testOrders, `.missing.field`) == `[null]`)
+	require(t, jq(testOrders, `.orders.user?`) == `[]`)
+	require(t, jq(testOrders, `[.. | .user? // empty]`) == `[["bob","alice","bob"]]`)
+	require(t, jq(testOrders, `."orders"[0]["id"]`) == `[1]`)
+}
+
+func TestJQ_construction(t *testing.T) {
+	require(t, jq(testOrders, `.orders[0] | {id, name: .user, n: (.tags | length)}`) == `[{"id":1,"n":1,"name":"bob"}]`)
+	require(t, jq(testOrders, `[.orders[] | .id * 2]`) == `[[2,4,6]]`)
+	require(t, jq(testOrders, `{(.orders[].user): 1}`) == `[{"bob":1},{"alice":1},{"bob":1}]`)
+	require(t, jq(testOrders, `.orders[0] | "\(.user) #\(.id)"`) == `["bob #1"]`)
+	require(t, jq(Value{}, `1, 2 | . + 1`) == `[2,3]`)
+	require(t, jq(Value{}, `[(1,2) * (10,20)]`) == `[[10,20,20,40]]`)
+	require(t, jq(Value{}, `{"a":{"b":1}} * {"a":{"c":2}}`) == `[{"a":{"b":1,"c":2}}]`)
+	require(t, jq(Value{}, `[1,2,2,3] - [2]`) == `[[1,3]]`)
+	require(t, jq(Value{}, `"a,b" / ","`) == `[["a","b"]]`)
+	require(t, jq(Value{}, `["ab" * 2.5, "ab" * 0, "" * 1e300]`) == `[["ababab",null,""]]`)
+	for _, n := range []string{"1e10", "9e18", "1e300", "(1e300 * 1e300)"} {
+		require(t, jq(Value{}, `"ab" * `+n) == `error: js: jq: repeat string result too long`)
+	}
+}
+
+func TestJQ_functions(t *testing.T) {
+	require(t, jq(testOrders, `.orders | map(select(.total > 5)) | map(.id)`) == `[[1,2]]`)
+	require(t, jq(testOrders, `.orders | map(.total) | add`) == `[39.5]`)
+	require(t, jq(testOrders, `.orders | group_by(.user) | map({user: .[0].user, n: length})`) == `[[{"n":1,"user":"alice"},{"n":2,"user":"bob"}]]`)
+	require(t, jq(testOrders, `.orders | sort_by(.total) | map(.id)`) == `[[3,1,2]]`)
+	require(t, jq(testOrders, `.orders | sort_by(.user, -.id) | map(.id)`) == `[[2,3,1]]`)
+	require(t, jq(testOrders, `.orders[0] | keys`) == `[["id","tags","total","user"]]`)
+	require(t, jq(testOrders, `.orders[0] | to_entries[0]`) == `[{"key":"id","value":1}]`)
+	require(t, jq(testOrders, `.orders[0] | with_entries(select(.key != "tags")) | keys`) == `[["id","total","user"]]`)
+	require(t, jq(testOrders, `[.orders[].user] | unique`) == `[["alice","bob"]]`)
+	require(t, jq(testOrders, `.orders | max_by(.total) | .id`) == `[2]`)
+	require(t, jq(testOrders, `[.orders[].tags[]] | join("-")`) == `["a-b-c"]`)
+	require(t, jq(testOrders, `.orders[0] | del(.tags, .total)`) == `[{"id":1,"user":"bob"}]`)
+	require(t, jq(testOrders, `.orders | del(.[] | select(.user == "bob")) | map(.id)`) == `[[2]]`)
+	require(t, jq(testOrders, `[.orders[] | .user | ascii_upcase | test("^B")]`) == `[[true,false,true]]`)
+	require(t, jq(Value{}, `"Éa-ßZ" | ascii_downcase, ascii_upcase`) == `["Éa-ßz","ÉA-ßZ"]`)
+	require(t, jq(testOrders, `[range(3)]`) == `[[0,1,2]]`)
+	require(t, jq(testOrders, `.orders[0].tags | first`) == `["a"]`)
+	require(t, jq(Value{}, `[[1,[2]],3] | flatten`) == `[[1,2,3]]`)
+	require(t, jq(Value{}, `[null, true, 1, "a", [], {}] | map(type)`) == `[["null","boolean","number","string","array","object"]]`)
+	require(t, jq(Value{}, `{"a":[1,2]} | contains({"a":[1]})`) == `[true]`)
+	require(t, jq(Value{}, `"12" | tonumber + 1`) == `[13]`)
+}
+
+func TestJQ_control(t *testing.T) {
+	require(t, jq(testOrders, `.orders[] | if .total > 20 then "big" elif .total > 5 then "mid" else "small" end`) == `["mid","big","small"]`)
+	require(t, jq(testOrders, `.orders[0] as $o | .orders | map(select(.user == $o.user)) | length`) == `[2]`)
+	require(t, jq(Value{}, `1, 2 as $x | $x`) == `[1,2]`)
+	require(t, jq(Value{}, `[1 + 2 as $x | $x * 10, 3]`) == `[[21,4]]`)
+	require(t, jq(Value{}, `[(1, 2) as $x | $x + 1] | add`) == `[5]`)
+	require(t, jq(testOrders, `reduce .orders[].total as $t (0; . + $t)`) == `[39.5]`)
+	require(t, jq(testOrders, `reduce .orders[] as $o ({}; . + {($o.user): ((.[$o.user] // 0) + $o.total)})`) == `[{"alice":25.5,"bob":14}]`)
+	require(t, jq(Value{}, `null // "default"`) == `["default"]`)
+	require(t, jq(Value{}, `true and (false or true) | not`) == `[false]`)
+	require(t, jq(Value{}, `error("boom")`) == `error: js: jq: boom`)
+	require(t, jq(Value{}, `$x`) == `error: js: jq: $x is not defined`)
+}
+
+func TestJQ_RunVars(t *testing.T) {
+	res, err := MustCompileJQ(`.orders | map(select(.user == $user)) | length`).RunVars(testOrders, Object{"user": "bob"})
+
+	require(t, err == nil && res.String() == `[2]`)
+}
+
+func TestValue_Transform(t *testing.T) {
+	v, err1 := testOrders.Transform(`{count: (.orders | length), users: [.orders[].user] | unique}`)
+	_, err2 := testOrders.Transform(`.orders[`)
+	none, err3 := testOrders.Transform(`empty`)
+	v2, err4 := NewValue(struct{ A int }{7}).Transform(`.A + 1`)
+
+	require(t, err1 == nil && v.JSON() == `{"count":3,"users":["alice","bob"]}`)
+	require(t, err2 != nil)
+	require(t, err3 == nil && none.IsNull())
+	require(t, err4 == nil && v2.Int() == 8)
+}
+
+func TestCompileJQ_fail(t *testing.T) {
+	for _, src := range []string{``, `.[`, `{a:}`, `foo`, `map(.)(`, `if . then 1`, `"abc`, `.a | `, `1 as $x`, `1 as x | .`} {
+		_, err := CompileJQ(src)
+		require(t, err != nil)
+	}
+}
@@ -0,0 +1,506 @@
+package js
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Dialect is the input syntax accepted by the parse functions (see ParseOptions).
+type Dialect int
+
+const (
+	// DialectJSON is strict JSON (RFC 8259).
+	DialectJSON Dialect = iota
+
+	// DialectJSONC is JSON with `//` and `/* */` comments and trailing commas.
+	DialectJSONC
+
+	// DialectJSON5 is JSON5 (https://json5.org): JSONC plus unquoted keys,
+	// single-quoted and multi-line strings, hexadecimal numbers, leading and
+	// trailing decimal points, explicit plus signs, Infinity and NaN.
+	DialectJSON5
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectJSON:
+		return "json"
+	case DialectJSONC:
+		return "jsonc"
+	case DialectJSON5:
+		return "json5"
+	}
+	return "unknown"
+}
+
+// ParseFileAuto parses the file choosing the dialect by its extension:
+// `.json5` files are parsed as JSON5, `.jsonc` files as JSONC,
+// other files with the dialect of opts.
+func ParseFileAuto(filename string, opts ...ParseOptions) (Value, error) {
+	o := parseOptions(opts)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json5":
+		o.Dialect = DialectJSON5
+	case ".jsonc":
+		o.Dialect = DialectJSONC
+	}
+	return ParseFile(filename, o)
+}
+
+// unmarshalDialect decodes a JSONC or JSON5 document into v.
+func unmarshalDialect(data []byte, v any, opts ParseOptions) error {
+	res, err := parseJSON5(data, opts)
+	if err != nil {
+		return err
+	}
+	switch p := v.(type) {
+	case *any:
+		*p = res
+		return nil
+	case *Object:
+		if obj, ok := res.(map[string]any); ok {
+			*p = obj
+			return nil
+		}
+		if res == nil {
+			*p = nil
+			return nil
+		}
+		return fmt.Errorf("js: cannot unmarshal %s into Object", typeName(res))
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+type json5Parser struct {
+	data  []byte
+	pos   int
+	opts  ParseOptions
+	json5 bool
+	depth int
+}
+
+func parseJSON5(data []byte, opts ParseOptions) (v any, err error) {
+	defer catch(&err)
+	p := &json5Parser{data: data, opts: opts, json5: opts.Dialect == DialectJSON5}
+	p.skip()
+	v = p.value()
+	if p.skip(); p.pos < len(data) {
+		p.fail("invalid character %s after top-level value", p.quoteChar())
+	}
+	return v, nil
+}
+
+func (p *json5Parser) fail(format string, args ...any) {
+	panic(newSyntaxError(p.data, int64(p.pos), format, args...))
+}
+
+func (p *json5Parser) quoteChar() string {
+	if p.pos >= len(p.data) {
+		return "EOF"
+	}
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *json5Parser) peek() byte {
+	if p.pos < len(p.data) {
+		return p.data[p.pos]
+	}
+	return 0
+}
+
+func (p *json5Parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.data[p.pos:min(len(p.data), p.pos+len(s))]), s)
+}
+
+// skip skips whitespace and comments.
+func (p *json5Parser) skip() {
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '/' && p.hasPrefix("//"):
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '/' && p.hasPrefix("/*"):
+			end := bytes.Index(p.data[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				p.fail("unterminated comment")
+			}
+			p.pos += end + 4
+		case p.json5 && c >= utf8.RuneSelf:
+			r, n := utf8.DecodeRune(p.data[p.pos:])
+			if !unicode.IsSpace(r) && r != '\uFEFF' {
+				return
+			}
+			p.pos += n
+		case p.json5 && (c == '\v' || c == '\f'):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *json5Parser) value() any {
+	switch c := p.peek(); {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"' || c == '\'' && p.json5:
+		return p.string()
+	case p.hasPrefix("true"):
+		p.pos += 4
+		return true
+	case p.hasPrefix("false"):
+		p.pos += 5
+		return false
+	case p.hasPrefix("null"):
+		p.pos += 4
+		return nil
+	case c == '-' || c >= '0' && c <= '9' || p.json5 && (c == '+' || c == '.' || c == 'I' || c == 'N'):
+		return p.number()
+	}
+	p.fail("invalid character %s looking for beginning of value", p.quoteChar())
+	return nil
+}
+
+func (p *json5Parser) enter() {
+	limit := p.opts.MaxDepth
+	if limit <= 0 {
+		limit = maxDecodeDepth
+	}
+	if p.depth++; p.depth > limit {
+		p.fail("exceeded max depth %d", limit)
+	}
+	p.pos++
+	p.skip()
+}
+
+func (p *json5Parser) object() any {
+	var m map[string]any
+	var ordered *OrderedObject
+	if p.opts.Ordered {
+		ordered = &OrderedObject{}
+	} else {
+		m = map[string]any{}
+	}
+	p.enter()
+	for p.peek() != '}' {
+		start := p.pos
+		key := p.key()
+		if p.skip(); p.peek() != ':' {
+			p.fail("invalid character %s after object key", p.quoteChar())
+		}
+		p.pos++
+		p.skip()
+		val := p.value()
+		if ordered != nil {
+			if p.opts.DisallowDuplicateKeys && ordered.Has(key) {
+				p.pos = start
+				p.fail("duplicate key %s", strconv.Quote(key))
+			}
+			ordered.Set(key, val)
+		} else {
+			if _, ok := m[key]; ok && p.opts.DisallowDuplicateKeys {
+				p.pos = start
+				p.fail("duplicate key %s", strconv.Quote(key))
+			}
+			m[key] = val
+		}
+		if !p.next('}') {
+			break
+		}
+	}
+	p.pos++
+	p.depth--
+	if ordered != nil {
+		return ordered
+	}
+	return m
+}
+
+func (p *json5Parser) array() any {
+	arr := []any{}
+	p.enter()
+	for p.peek() != ']' {
+		arr = append(arr, p.value())
+		if !p.next(']') {
+			break
+		}
+	}
+	p.pos++
+	p.depth--
+	return arr
+}
+
+// next skips the separator after an element and reports whether
+// another element follows; a trailing comma before end is allowed.
+func (p *json5Parser) next(end byte) bool {
+	switch p.skip(); p.peek() {
+	case ',':
+		p.pos++
+		p.skip()
+		return p.peek() != end
+	case end:
+		return false
+	}
+	if end == '}' {
+		p.fail("invalid character %s after object key:value pair", p.quoteChar())
+	}
+	p.fail("invalid character %s after array element", p.quoteChar())
+	return false
+}
+
+func (p *json5Parser) key() string {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'' && p.json5:
+		return p.string()
+	case p.json5:
+		start := p.pos
+		for p.pos < len(p.data) {
+			r, n := utf8.DecodeRune(p.data[p.pos:])
+			if !(r == '_' || r == '$' || unicode.IsLetter(r) || p.pos > start && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r))) {
+				break
+			}
+			p.pos += n
+		}
+		if p.pos > start {
+			return string(p.data[start:p.pos])
+		}
+	}
+	p.fail("invalid character %s looking for beginning of object key string", p.quoteChar())
+	return ""
+}
+
+func (p *json5Parser) string() string {
+	quote := p.data[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for {
+		start := p.pos
+		for p.pos < len(p.data) {
+			if c := p.data[p.pos]; c == quote || c == '\\' || c < ' ' {
+				break
+			}
+			p.pos++
+		}
+		sb.Write(p.data[start:p.pos])
+		if p.pos >= len(p.data) {
+			p.fail("unterminated string")
+		}
+		switch c := p.data[p.pos]; {
+		case c == quote:
+			p.pos++
+			s := sb.String()
+			if !utf8.ValidString(s) {
+				s = strings.ToValidUTF8(s, "\uFFFD")
+			}
+			if p.opts.DisallowNaN {
+				if f, err := strconv.ParseFloat(s, 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+					p.fail("NaN-like string %s", strconv.Quote(s))
+				}
+			}
+			return s
+		case c == '\t' && p.json5:
+			sb.WriteByte(c)
+			p.pos++
+		case c < ' ':
+			p.fail("invalid character %s in string literal", p.quoteChar())
+		default:
+			p.escape(&sb)
+		}
+	}
+}
+
+func (p *json5Parser) escape(sb *strings.Builder) {
+	p.pos++ // backslash
+	if p.pos >= len(p.data) {
+		p.fail("unterminated string")
+	}
+	c := p.data[p.pos]
+	p.pos++
+	switch c {
+	case '"', '\\', '/':
+		sb.WriteByte(c)
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'u':
+		r := p.hex(4)
+		if utf16.IsSurrogate(r) && p.hasPrefix(`\u`) {
+			p.pos += 2
+			r = utf16.DecodeRune(r, p.hex(4))
+		}
+		sb.WriteRune(r)
+	default:
+		if !p.json5 {
+			p.pos--
+			p.fail("invalid character %s in string escape code", p.quoteChar())
+		}
+		switch c {
+		case 'v':
+			sb.WriteByte('\v')
+		case '0':
+			sb.WriteByte(0)
+		case 'x':
+			sb.WriteRune(p.hex(2))
+		case '\r': // line continuation
+			if p.peek() == '\n' {
+				p.pos++
+			}
+		case '\n':
+		default:
+			if c >= '1' && c <= '9' {
+				p.pos--
+				p.fail("invalid character %s in string escape code", p.quoteChar())
+			}
+			p.pos--
+			r, n := utf8.DecodeRune(p.data[p.pos:])
+			if r != '\u2028' && r != '\u2029' { // line continuation
+				sb.WriteRune(r)
+			}
+			p.pos += n
+		}
+	}
+}
+
+func (p *json5Parser) hex(n int) rune {
+	if p.pos+n > len(p.data) {
+		p.fail("unterminated string")
+	}
+	v, err := strconv.ParseUint(string(p.data[p.pos:p.pos+n]), 16, 32)
+	if err != nil {
+		p.fail("invalid character %s in string escape code", p.quoteChar())
+	}
+	p.pos += n
+	return rune(v)
+}
+
+func (p *json5Parser) number() any {
+	start := p.pos
+	sign := ""
+	if c := p.peek(); c == '-' || c == '+' && p.json5 {
+		if c == '-' {
+			sign = "-"
+		}
+		p.pos++
+	}
+	switch {
+	case p.json5 && p.hasPrefix("Infinity"):
+		p.pos += len("Infinity")
+		if sign == "-" {
+			return p.special(start, math.Inf(-1))
+		}
+		return p.special(start, math.Inf(1))
+	case p.json5 && p.hasPrefix("NaN"):
+		p.pos += len("NaN")
+		return p.special(start, math.NaN())
+	case p.json5 && (p.hasPrefix("0x") || p.hasPrefix("0X")):
+		p.pos += 2
+		digits := p.pos
+		for p.pos < len(p.data) && strings.IndexByte("0123456789abcdefABCDEF", p.data[p.pos]) >= 0 {
+			p.pos++
+		}
+		n, ok := new(big.Int).SetString(sign+string(p.data[digits:p.pos]), 16)
+		if !ok {
+			p.fail("invalid hexadecimal number")
+		}
+		return p.numberValue(n.String())
+	}
+	intStart := p.pos
+	for p.pos < len(p.data) && isDigit(p.data[p.pos]) {
+		p.pos++
+	}
+	intPart := string(p.data[intStart:p.pos])
+	if len(intPart) > 1 && intPart[0] == '0' {
+		p.pos = intStart + 1
+		p.fail("invalid character %s after leading zero in numeric literal", p.quoteChar())
+	}
+	frac := ""
+	if p.peek() == '.' {
+		p.pos++
+		fracStart := p.pos
+		for p.pos < len(p.data) && isDigit(p.data[p.pos]) {
+			p.pos++
+		}
+		frac = string(p.data[fracStart:p.pos])
+		if frac == "" && (!p.json5 || intPart == "") {
+			p.fail("invalid character %s after decimal point in numeric literal", p.quoteChar())
+		}
+	}
+	if intPart == "" {
+		if frac == "" {
+			p.fail("invalid character %s in numeric literal", p.quoteChar())
+		}
+		intPart = "0"
+	}
+	exp := ""
+	if c := p.peek(); c == 'e' || c == 'E' {
+		expStart := p.pos
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		digits := p.pos
+		for p.pos < len(p.data) && isDigit(p.data[p.pos]) {
+			p.pos++
+		}
+		if p.pos == digits {
+			p.fail("invalid character %s in exponent of numeric literal", p.quoteChar())
+		}
+		exp = string(p.data[expStart:p.pos])
+	}
+	lit := sign + intPart
+	if frac != "" {
+		lit += "." + frac
+	}
+	return p.numberValue(lit + exp)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// numberValue returns the normalized JSON literal as json.Number or float64.
+func (p *json5Parser) numberValue(lit string) any {
+	if p.opts.UseNumber {
+		return json.Number(lit)
+	}
+	f, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		p.fail("number %s out of range", lit)
+	}
+	return f
+}
+
+func (p *json5Parser) special(start int, f float64) any {
+	if p.opts.DisallowNaN {
+		lit := string(p.data[start:p.pos])
+		p.pos = start
+		p.fail("NaN-like number %s", lit)
+	}
+	return f
+}
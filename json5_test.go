@@ -0,0 +1,115 @@
+package js
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse_JSONC(t *testing.T) {
+	src := `// config
+{
+	"name": "app", /* inline */
+	"ports": [80, 443,],
+	"url": "http://x/*not a comment*/", // trailing comma
+}`
+	v, err := Parse([]byte(src), ParseOptions{Dialect: DialectJSONC})
+
+	require(t, err == nil)
+	require(t, v.JSON() == `{"name":"app","ports":[80,443],"url":"http://x/*not a comment*/"}`)
+
+	obj, err := ParseObject([]byte(src), ParseOptions{Dialect: DialectJSONC})
+	require(t, err == nil && obj.GetStr("name") == "app")
+
+	for _, bad := range []string{`{a:1}`, `{'a':1}`, `[0x10]`, `[NaN]`, `[1,,2]`, `[,]`, `{"a":1 /* x`, `[01]`, `["\x41"]`} {
+		_, err := Parse([]byte(bad), ParseOptions{Dialect: DialectJSONC})
+		var se *SyntaxError
+		require(t, errors.As(err, &se))
+	}
+	_, err = Parse([]byte(src))
+	require(t, err != nil)
+}
+
+func TestParse_JSON5(t *testing.T) {
+	src := `{
+  // comments
+  unquoted: 'and you can quote me on that',
+  singleQuotes: 'I can use "double quotes" here',
+  lineBreaks: "Look, Mom! \
+No \\n's!",
+  hexadecimal: 0xdecaf,
+  leadingDecimalPoint: .8675309, andTrailing: 8675309.,
+  positiveSign: +1,
+  trailingComma: 'in objects', andIn: ['arrays',],
+  "backwardsCompatible": "with JSON",
+  $id_1: '\x41é\'',
+}`
+	v, err := Parse([]byte(src), ParseOptions{Dialect: DialectJSON5})
+
+	require(t, err == nil)
+	require(t, v.Path("unquoted").String() == "and you can quote me on that")
+	require(t, v.Path("singleQuotes").String() == `I can use "double quotes" here`)
+	require(t, v.Path("lineBreaks").String() == `Look, Mom! No \n's!`)
+	require(t, v.Path("hexadecimal").Int() == 0xdecaf)
+	require(t, v.Path("leadingDecimalPoint").Float64() == .8675309)
+	require(t, v.Path("andTrailing").Float64() == 8675309)
+	require(t, v.Path("positiveSign").Int() == 1)
+	require(t, v.Path("andIn[0]").String() == "arrays")
+	require(t, v.Path("$id_1").String() == "Aé'")
+
+	v, err = Parse([]byte(`[Infinity, -Infinity, NaN, -0x10]`), ParseOptions{Dialect: DialectJSON5})
+	require(t, err == nil)
+	require(t, math.IsInf(v.Path("[0]").Float64(), 1) && math.IsInf(v.Path("[1]").Float64(), -1))
+	require(t, math.IsNaN(v.Path("[2]").Float64()) && v.Path("[3]").Int() == -16)
+
+	v, err = Parse([]byte(`{b: 0x1FFFFFFFFFFFFFFF, a: .5}`), ParseOptions{Dialect: DialectJSON5, UseNumber: true, Ordered: true})
+	require(t, err == nil && v.JSON() == `{"b":2305843009213693951,"a":0.5}`)
+}
+
+func TestParse_JSON5Errors(t *testing.T) {
+	opts := ParseOptions{Dialect: DialectJSON5}
+	var se *SyntaxError
+
+	_, err := Parse([]byte("{\n  a: 1,\n  b: ?\n}"), opts)
+	require(t, errors.As(err, &se) && se.Line == 3 && se.Column == 6)
+
+	_, err = Parse([]byte(`{a: 1, a: 2}`), ParseOptions{Dialect: DialectJSON5, DisallowDuplicateKeys: true})
+	require(t, errors.As(err, &se) && se.Offset == 7)
+	_, err = Parse([]byte(`[NaN]`), ParseOptions{Dialect: DialectJSON5, DisallowNaN: true})
+	require(t, errors.As(err, &se) && se.Offset == 1)
+	_, err = Parse([]byte(`[[[1]]]`), ParseOptions{Dialect: DialectJSON5, MaxDepth: 2})
+	require(t, errors.As(err, &se) && se.Offset == 2)
+	for _, d := range []Dialect{DialectJSONC, DialectJSON5} {
+		_, err = Parse(bytes.Repeat([]byte("["), 1_000_000), ParseOptions{Dialect: d})
+		require(t, errors.As(err, &se) && se.Msg == "exceeded max depth 10000")
+	}
+
+	for _, bad := range []string{`{a b}`, `'abc`, `[.]`, `[1e]`, `{1a: 1}`, `[1] 2`, `"a` + "\n" + `"`} {
+		_, err := Parse([]byte(bad), opts)
+		require(t, errors.As(err, &se))
+	}
+}
+
+func TestParseFileAuto(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		filename := filepath.Join(dir, name)
+		require(t, os.WriteFile(filename, []byte(data), 0600) == nil)
+		return filename
+	}
+
+	v, err := ParseFileAuto(write("a.json5", `{a: 'x', /* c */}`))
+	require(t, err == nil && v.Path("a").String() == "x")
+	v, err = ParseFileAuto(write("b.JSONC", `{"a": 1, // c
+	}`))
+	require(t, err == nil && v.Path("a").Int() == 1)
+
+	_, err = ParseFileAuto(write("c.jsonc", `{a: 1}`))
+	var se *SyntaxError
+	require(t, errors.As(err, &se) && se.File == filepath.Join(dir, "c.jsonc"))
+	_, err = ParseFileAuto(write("d.json", `{"a": 1,}`))
+	require(t, err != nil)
+}
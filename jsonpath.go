@@ -0,0 +1,910 @@
+package js
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONPath is a compiled JSONPath query (RFC 9535).
+type JSONPath struct {
+	src   string
+	query *jpQuery
+}
+
+// CompileJSONPath parses a JSONPath query like `$.store.book[?@.price < 10].title`.
+func CompileJSONPath(expr string) (p *JSONPath, err error) {
+	defer catch(&err)
+	ps := &jpParser{src: expr}
+	if ps.peek() != '$' {
+		ps.fail("query must start with `$`")
+	}
+	q := ps.parseQuery()
+	if ps.pos < len(ps.src) {
+		ps.fail("unexpected `%s`", ps.src[ps.pos:])
+	}
+	return &JSONPath{expr, q}, nil
+}
+
+// MustCompileJSONPath parses a JSONPath query and panics on error.
+func MustCompileJSONPath(expr string) *JSONPath {
+	return must(CompileJSONPath(expr))
+}
+
+// String returns the source text of the query.
+func (p *JSONPath) String() string {
+	return p.src
+}
+
+// Select returns all values matched by the query, in document order.
+// Members of objects are visited in sorted key order.
+func (p *JSONPath) Select(v Value) Array {
+	nodes := p.query.nodes(v.val, v.val)
+	if nodes == nil {
+		return Array{}
+	}
+	return nodes
+}
+
+// Query evaluates a JSONPath query (RFC 9535) against the value
+// and returns the matched values.
+func (v Value) Query(expr string) (Array, error) {
+	p, err := CompileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Select(v), nil
+}
+
+//--------- AST ----------
+
+type jpQuery struct {
+	relative bool // `@` instead of `$`
+	segs     []jpSegment
+}
+
+type jpSegment struct {
+	descendant bool
+	selectors  []*jpSelector
+}
+
+type jpSelectorKind byte
+
+const (
+	jpName jpSelectorKind = iota
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpFilter
+)
+
+type jpSelector struct {
+	kind   jpSelectorKind
+	name   string
+	index  int
+	slice  [3]*int // start, end, step
+	filter jpLogical
+}
+
+// jpLogical is a filter expression of LogicalType.
+type jpLogical interface {
+	test(root, cur any) bool
+}
+
+// jpComparable is an expression of ValueType; ok=false means Nothing.
+type jpComparable interface {
+	value(root, cur any) (v any, ok bool)
+}
+
+type jpOr []jpLogical
+type jpAnd []jpLogical
+type jpNot struct{ x jpLogical }
+type jpExists struct{ q *jpQuery }
+type jpLiteral struct{ v any }
+type jpSingular struct{ q *jpQuery }
+
+type jpCompare struct {
+	op   string
+	l, r jpComparable
+}
+
+type jpType byte
+
+const (
+	jpValueType jpType = iota
+	jpLogicalType
+	jpNodesType
+)
+
+type jpArg struct {
+	val   jpComparable // ValueType argument
+	nodes *jpQuery     // NodesType argument
+}
+
+type jpFunc struct {
+	name string
+	args []jpArg
+}
+
+var jpFunctions = map[string]struct {
+	result jpType
+	params []jpType
+}{
+	"length": {jpValueType, []jpType{jpValueType}},
+	"count":  {jpValueType, []jpType{jpNodesType}},
+	"match":  {jpLogicalType, []jpType{jpValueType, jpValueType}},
+	"search": {jpLogicalType, []jpType{jpValueType, jpValueType}},
+	"value":  {jpValueType, []jpType{jpNodesType}},
+}
+
+//--------- evaluation ----------
+
+func (q *jpQuery) nodes(root, cur any) []any {
+	nodes := []any{root}
+	if q.relative {
+		nodes[0] = cur
+	}
+	for _, seg := range q.segs {
+		var out []any
+		for _, n := range nodes {
+			if seg.descendant {
+				out = seg.descend(n, root, out)
+			} else {
+				out = seg.apply(n, root, out)
+			}
+		}
+		if nodes = out; len(nodes) == 0 {
+			return nil
+		}
+	}
+	return nodes
+}
+
+func (q *jpQuery) isSingular() bool {
+	for _, seg := range q.segs {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		if k := seg.selectors[0].kind; k != jpName && k != jpIndex {
+			return false
+		}
+	}
+	return true
+}
+
+func (seg jpSegment) apply(n, root any, out []any) []any {
+	for _, sel := range seg.selectors {
+		out = sel.apply(n, root, out)
+	}
+	return out
+}
+
+func (seg jpSegment) descend(n, root any, out []any) []any {
+	out = seg.apply(n, root, out)
+	for _, child := range jpChildren(n) {
+		out = seg.descend(child, root, out)
+	}
+	return out
+}
+
+// jpChildren returns the elements of an array or the member values
+// of an object (in sorted key order).
+func jpChildren(n any) []any {
+	if arr, obj := jpContainer(n); arr != nil {
+		return arr
+	} else if obj != nil {
+		vv := make([]any, 0, len(obj))
+		for _, k := range obj.Keys() {
+			vv = append(vv, obj[k])
+		}
+		return vv
+	}
+	return nil
+}
+
+func jpContainer(n any) (Array, Object) {
+	switch KindOf(n) {
+	case KindArray, KindObject:
+	case KindNull:
+		if n == nil {
+			return nil, nil
+		}
+		// a typed nil like a nil slice is an empty container
+	default:
+		return nil, nil // scalars are not converted
+	}
+	if arr, ok := asArray(n); ok {
+		if arr == nil {
+			arr = Array{}
+		}
+		return arr, nil
+	}
+	obj, _ := asObject(n)
+	return nil, obj
+}
+
+func (sel *jpSelector) apply(n, root any, out []any) []any {
+	arr, obj := jpContainer(n)
+	switch sel.kind {
+	case jpName:
+		if v, ok := obj[sel.name]; ok && obj != nil {
+			out = append(out, v)
+		}
+	case jpWildcard:
+		out = append(out, jpChildren(n)...)
+	case jpIndex:
+		if i := sel.index; arr != nil {
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				out = append(out, arr[i])
+			}
+		}
+	case jpSlice:
+		if arr != nil {
+			out = sel.applySlice(arr, out)
+		}
+	case jpFilter:
+		for _, child := range jpChildren(n) {
+			if sel.filter.test(root, child) {
+				out = append(out, child)
+			}
+		}
+	}
+	return out
+}
+
+func (sel *jpSelector) applySlice(arr Array, out []any) []any {
+	n, step := len(arr), 1
+	if sel.slice[2] != nil {
+		step = *sel.slice[2]
+	}
+	if step == 0 {
+		return out
+	}
+	norm := func(i int) int {
+		if i < 0 {
+			return i + n
+		}
+		return i
+	}
+	start, end := 0, n
+	if step < 0 {
+		start, end = n-1, -1
+	}
+	if sel.slice[0] != nil {
+		start = norm(*sel.slice[0])
+	}
+	if sel.slice[1] != nil {
+		end = norm(*sel.slice[1])
+	}
+	if step > 0 {
+		lower, upper := min(max(start, 0), n), min(max(end, 0), n)
+		for i := lower; i < upper; i += step {
+			out = append(out, arr[i])
+		}
+	} else {
+		upper, lower := min(max(start, -1), n-1), min(max(end, -1), n-1)
+		for i := upper; i > lower; i += step {
+			out = append(out, arr[i])
+		}
+	}
+	return out
+}
+
+func (x jpOr) test(root, cur any) bool {
+	for _, e := range x {
+		if e.test(root, cur) {
+			return true
+		}
+	}
+	return false
+}
+
+func (x jpAnd) test(root, cur any) bool {
+	for _, e := range x {
+		if !e.test(root, cur) {
+			return false
+		}
+	}
+	return true
+}
+
+func (x jpNot) test(root, cur any) bool {
+	return !x.x.test(root, cur)
+}
+
+func (x jpExists) test(root, cur any) bool {
+	return len(x.q.nodes(root, cur)) > 0
+}
+
+func (x jpLiteral) value(root, cur any) (any, bool) {
+	return x.v, true
+}
+
+func (x jpSingular) value(root, cur any) (any, bool) {
+	if nodes := x.q.nodes(root, cur); len(nodes) == 1 {
+		return nodes[0], true
+	}
+	return nil, false
+}
+
+func (x jpCompare) test(root, cur any) bool {
+	l, lok := x.l.value(root, cur)
+	r, rok := x.r.value(root, cur)
+	switch x.op {
+	case "==":
+		return jpEqual(l, lok, r, rok)
+	case "!=":
+		return !jpEqual(l, lok, r, rok)
+	case "<":
+		return jpLess(l, lok, r, rok)
+	case "<=":
+		return jpLess(l, lok, r, rok) || jpEqual(l, lok, r, rok)
+	case ">":
+		return jpLess(r, rok, l, lok)
+	case ">=":
+		return jpLess(r, rok, l, lok) || jpEqual(l, lok, r, rok)
+	}
+	return false
+}
+
+func jpEqual(a any, aok bool, b any, bok bool) bool {
+	if !aok || !bok {
+		return !aok && !bok
+	}
+	return deepEqual(a, b)
+}
+
+// jpLess orders numbers and strings only (via Cmp); other values are unordered.
+func jpLess(a any, aok bool, b any, bok bool) bool {
+	if !aok || !bok {
+		return false
+	}
+	if IsNum(a) && IsNum(b) {
+		return Cmp(a, b) < 0
+	}
+	sa, ok1 := a.(string)
+	sb, ok2 := b.(string)
+	return ok1 && ok2 && Cmp(sa, sb) < 0
+}
+
+func (f *jpFunc) value(root, cur any) (any, bool) {
+	switch f.name {
+	case "length":
+		v, ok := f.args[0].val.value(root, cur)
+		if !ok {
+			return nil, false
+		}
+		if s, ok := v.(string); ok {
+			return utf8.RuneCountInString(s), true
+		}
+		if arr, obj := jpContainer(v); arr != nil {
+			return len(arr), true
+		} else if obj != nil {
+			return len(obj), true
+		}
+		return nil, false
+	case "count":
+		return len(f.args[0].nodes.nodes(root, cur)), true
+	case "value":
+		if nodes := f.args[0].nodes.nodes(root, cur); len(nodes) == 1 {
+			return nodes[0], true
+		}
+	}
+	return nil, false
+}
+
+func (f *jpFunc) test(root, cur any) bool {
+	s, ok1 := f.args[0].val.value(root, cur)
+	re, ok2 := f.args[1].val.value(root, cur)
+	str, ok3 := s.(string)
+	pattern, ok4 := re.(string)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return false
+	}
+	rx := jpRegexp(pattern, f.name == "match")
+	return rx != nil && rx.MatchString(str)
+}
+
+// The compiled patterns of match() and search(); the cache is cleared
+// when it grows to maxRegexpCache entries, as patterns may come from input.
+var (
+	jpRegexpMu    sync.Mutex
+	jpRegexpCache = map[jpRegexpKey]*regexp.Regexp{} // nil if invalid
+)
+
+const maxRegexpCache = 256
+
+type jpRegexpKey struct {
+	pattern string
+	full    bool
+}
+
+// jpRegexp compiles an I-Regexp (RFC 9485) pattern; `.` does not match
+// line terminators \n and \r.
+func jpRegexp(pattern string, full bool) *regexp.Regexp {
+	key := jpRegexpKey{pattern, full}
+	jpRegexpMu.Lock()
+	rx, ok := jpRegexpCache[key]
+	jpRegexpMu.Unlock()
+	if ok {
+		return rx
+	}
+	var sb strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			sb.WriteByte(c)
+			i++
+			sb.WriteByte(pattern[i])
+		case c == '[':
+			inClass = true
+			sb.WriteByte(c)
+		case c == ']':
+			inClass = false
+			sb.WriteByte(c)
+		case c == '.' && !inClass:
+			sb.WriteString(`[^\n\r]`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	src := sb.String()
+	if full {
+		src = `^(?:` + src + `)$`
+	}
+	rx, err := regexp.Compile(src)
+	if err != nil {
+		rx = nil
+	}
+	jpRegexpMu.Lock()
+	if len(jpRegexpCache) >= maxRegexpCache {
+		clear(jpRegexpCache)
+	}
+	jpRegexpCache[key] = rx
+	jpRegexpMu.Unlock()
+	return rx
+}
+
+//--------- parser ----------
+
+type jpParser struct {
+	src string
+	pos int
+}
+
+func (p *jpParser) fail(format string, args ...any) {
+	panic(fmt.Errorf("js: invalid JSONPath `%s` at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...)))
+}
+
+func (p *jpParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *jpParser) skipS() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *jpParser) eat(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *jpParser) expect(s string) {
+	if !p.eat(s) {
+		p.fail("expected `%s`", s)
+	}
+}
+
+// parseQuery parses `$` or `@` followed by segments.
+func (p *jpParser) parseQuery() *jpQuery {
+	q := &jpQuery{relative: p.peek() == '@'}
+	p.pos++
+	for {
+		save := p.pos
+		p.skipS()
+		switch {
+		case p.eat(".."):
+			seg := jpSegment{descendant: true}
+			switch c := p.peek(); {
+			case c == '[':
+				seg.selectors = p.parseBracketed()
+			case c == '*':
+				p.pos++
+				seg.selectors = []*jpSelector{{kind: jpWildcard}}
+			default:
+				seg.selectors = []*jpSelector{{kind: jpName, name: p.parseMemberName()}}
+			}
+			q.segs = append(q.segs, seg)
+		case p.eat("."):
+			var sel *jpSelector
+			if p.eat("*") {
+				sel = &jpSelector{kind: jpWildcard}
+			} else {
+				sel = &jpSelector{kind: jpName, name: p.parseMemberName()}
+			}
+			q.segs = append(q.segs, jpSegment{selectors: []*jpSelector{sel}})
+		case p.peek() == '[':
+			q.segs = append(q.segs, jpSegment{selectors: p.parseBracketed()})
+		default:
+			p.pos = save
+			return q
+		}
+	}
+}
+
+func (p *jpParser) parseMemberName() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !(r == '_' || r >= 0x80 || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' ||
+			p.pos > start && '0' <= r && r <= '9') {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		p.fail("expected member name")
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *jpParser) parseBracketed() (sels []*jpSelector) {
+	p.expect("[")
+	for {
+		p.skipS()
+		sels = append(sels, p.parseSelector())
+		p.skipS()
+		if p.eat("]") {
+			return
+		}
+		p.expect(",")
+	}
+}
+
+func (p *jpParser) parseSelector() *jpSelector {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		return &jpSelector{kind: jpName, name: p.parseString()}
+	case c == '*':
+		p.pos++
+		return &jpSelector{kind: jpWildcard}
+	case c == '?':
+		p.pos++
+		p.skipS()
+		return &jpSelector{kind: jpFilter, filter: p.parseOr()}
+	}
+	sel := &jpSelector{kind: jpIndex}
+	var start *int
+	if c := p.peek(); c == '-' || '0' <= c && c <= '9' {
+		i := p.parseInt()
+		start = &i
+	}
+	p.skipS()
+	if !p.eat(":") {
+		if start == nil {
+			p.fail("expected selector")
+		}
+		sel.index = *start
+		return sel
+	}
+	sel.kind, sel.slice[0] = jpSlice, start
+	p.skipS()
+	if c := p.peek(); c == '-' || '0' <= c && c <= '9' {
+		i := p.parseInt()
+		sel.slice[1] = &i
+		p.skipS()
+	}
+	if p.eat(":") {
+		p.skipS()
+		if c := p.peek(); c == '-' || '0' <= c && c <= '9' {
+			i := p.parseInt()
+			sel.slice[2] = &i
+		}
+	}
+	return sel
+}
+
+const jpMaxInt = 1<<53 - 1
+
+func (p *jpParser) parseInt() int {
+	start := p.pos
+	p.eat("-")
+	digits := p.pos
+	for p.pos < len(p.src) && '0' <= p.src[p.pos] && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	s := p.src[start:p.pos]
+	if p.pos == digits || p.src[digits] == '0' && (p.pos-digits > 1 || digits > start) {
+		p.fail("invalid integer `%s`", s)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n > jpMaxInt || n < -jpMaxInt {
+		p.fail("integer out of range `%s`", s)
+	}
+	return int(n)
+}
+
+func (p *jpParser) parseString() string {
+	q := p.src[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			p.fail("unterminated string")
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == q:
+			p.pos++
+			return sb.String()
+		case c < 0x20:
+			p.fail("control character in string")
+		case c == '\\':
+			p.pos++
+			switch e := p.peek(); e {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '/', '\\':
+				sb.WriteByte(e)
+			case '\'', '"':
+				if e != q {
+					p.fail("invalid escape `\\%c`", e)
+				}
+				sb.WriteByte(e)
+			case 'u':
+				p.pos++
+				r := p.parseHex4()
+				if utf16.IsSurrogate(r) {
+					if r >= 0xDC00 || !p.eat(`\u`) {
+						p.fail("invalid surrogate pair")
+					}
+					r2 := p.parseHex4()
+					if r = utf16.DecodeRune(r, r2); r == utf8.RuneError {
+						p.fail("invalid surrogate pair")
+					}
+				}
+				sb.WriteRune(r)
+				continue
+			default:
+				p.fail("invalid escape")
+			}
+			p.pos++
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *jpParser) parseHex4() rune {
+	if p.pos+4 > len(p.src) {
+		p.fail("invalid unicode escape")
+	}
+	n, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		p.fail("invalid unicode escape")
+	}
+	p.pos += 4
+	return rune(n)
+}
+
+func (p *jpParser) parseOr() jpLogical {
+	x := jpOr{p.parseAnd()}
+	for {
+		save := p.pos
+		p.skipS()
+		if !p.eat("||") {
+			p.pos = save
+			break
+		}
+		p.skipS()
+		x = append(x, p.parseAnd())
+	}
+	if len(x) == 1 {
+		return x[0]
+	}
+	return x
+}
+
+func (p *jpParser) parseAnd() jpLogical {
+	x := jpAnd{p.parseBasic()}
+	for {
+		save := p.pos
+		p.skipS()
+		if !p.eat("&&") {
+			p.pos = save
+			break
+		}
+		p.skipS()
+		x = append(x, p.parseBasic())
+	}
+	if len(x) == 1 {
+		return x[0]
+	}
+	return x
+}
+
+func (p *jpParser) parseBasic() jpLogical {
+	if p.eat("!") {
+		p.skipS()
+		if p.eat("(") {
+			return jpNot{p.parseParen()}
+		}
+		return jpNot{p.parseTest()}
+	}
+	if p.eat("(") {
+		return p.parseParen()
+	}
+	start := p.pos
+	left, ok := p.tryComparable()
+	save := p.pos
+	p.skipS()
+	op := p.parseCmpOp()
+	if op == "" {
+		p.pos = save
+		if ok && left != nil {
+			p.fail("comparison operator expected")
+		}
+		p.pos = start
+		return p.parseTest()
+	}
+	if left == nil {
+		p.pos = start
+		p.fail("value expected before `%s`", op)
+	}
+	p.skipS()
+	right, _ := p.tryComparable()
+	if right == nil {
+		p.fail("value expected after `%s`", op)
+	}
+	return jpCompare{op, left, right}
+}
+
+func (p *jpParser) parseParen() jpLogical {
+	p.skipS()
+	x := p.parseOr()
+	p.skipS()
+	p.expect(")")
+	return x
+}
+
+func (p *jpParser) parseCmpOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.eat(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// parseTest parses a filter query (existence test) or a LogicalType function.
+func (p *jpParser) parseTest() jpLogical {
+	if c := p.peek(); c == '@' || c == '$' {
+		return jpExists{p.parseQuery()}
+	}
+	f, typ := p.parseFunc()
+	if typ != jpLogicalType {
+		p.fail("function `%s` result must be compared", f.name)
+	}
+	return f
+}
+
+// tryComparable parses a literal, singular query or ValueType function.
+// A non-singular query or a LogicalType function yields (nil, false)
+// with the input consumed, so that it can be re-parsed as a test.
+func (p *jpParser) tryComparable() (jpComparable, bool) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		q := p.parseQuery()
+		if !q.isSingular() {
+			return nil, false
+		}
+		return jpSingular{q}, false
+	case c == '\'' || c == '"':
+		return jpLiteral{p.parseString()}, true
+	case c == '-' || '0' <= c && c <= '9':
+		return jpLiteral{p.parseNumber()}, true
+	}
+	for lit, v := range map[string]any{"true": true, "false": false, "null": nil} {
+		if strings.HasPrefix(p.src[p.pos:], lit) && !p.isFuncCall(len(lit)) {
+			p.pos += len(lit)
+			return jpLiteral{v}, true
+		}
+	}
+	f, typ := p.parseFunc()
+	if typ != jpValueType {
+		return nil, false
+	}
+	return f, false
+}
+
+func (p *jpParser) isFuncCall(n int) bool {
+	rest := p.src[p.pos+n:]
+	return len(rest) > 0 && (rest[0] == '(' || rest[0] == '_' || 'a' <= rest[0] && rest[0] <= 'z' || '0' <= rest[0] && rest[0] <= '9')
+}
+
+func (p *jpParser) parseNumber() float64 {
+	start := p.pos
+	p.eat("-")
+	for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	s := p.src[start:p.pos]
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || !jsonNumberRx.MatchString(s) {
+		p.fail("invalid number `%s`", s)
+	}
+	return f
+}
+
+var jsonNumberRx = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+func (p *jpParser) parseFunc() (*jpFunc, jpType) {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !(c == '_' || 'a' <= c && c <= 'z' || p.pos > start && '0' <= c && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		p.fail("expression expected")
+	}
+	def, ok := jpFunctions[name]
+	if !ok {
+		p.pos = start
+		p.fail("unknown function `%s`", name)
+	}
+	p.expect("(")
+	f := &jpFunc{name: name}
+	for i, typ := range def.params {
+		p.skipS()
+		if i > 0 {
+			p.expect(",")
+			p.skipS()
+		}
+		switch typ {
+		case jpNodesType:
+			if c := p.peek(); c != '@' && c != '$' {
+				p.fail("function `%s` expects a query argument", name)
+			}
+			f.args = append(f.args, jpArg{nodes: p.parseQuery()})
+		case jpValueType:
+			v, _ := p.tryComparable()
+			if v == nil {
+				p.fail("function `%s` expects a value argument", name)
+			}
+			f.args = append(f.args, jpArg{val: v})
+		}
+	}
+	p.skipS()
+	p.expect(")")
+	return f, def.result
+}
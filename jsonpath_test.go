@@ -0,0 +1,102 @@
+package js
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var testStore = MustParse([]byte(`{"store":{
+	"book":[
+		{"category":"reference","author":"Nigel Rees","title":"Sayings of the Century","price":8.95},
+		{"category":"fiction","author":"Evelyn Waugh","title":"Sword of Honour","price":12.99},
+		{"category":"fiction","author":"Herman Melville","title":"Moby Dick","isbn":"0-553-21311-3","price":8.99},
+		{"category":"fiction","author":"J. R. R. Tolkien","title":"The Lord of the Rings","isbn":"0-395-19395-8","price":22.99}
+	],
+	"bicycle":{"color":"red","price":399}
+}}`))
+
+func query(v Value, expr string) string {
+	res, err := v.Query(expr)
+	if err != nil {
+		return "error"
+	}
+	return res.String()
+}
+
+func TestValue_Query(t *testing.T) {
+	require(t, query(testStore, `$.store.book[*].author`) == `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`)
+	require(t, query(testStore, `$..author`) == `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`)
+	require(t, query(testStore, `$.store..price`) == `[399,8.95,12.99,8.99,22.99]`)
+	require(t, query(testStore, `$..book[2].title`) == `["Moby Dick"]`)
+	require(t, query(testStore, `$..book[-1].title`) == `["The Lord of the Rings"]`)
+	require(t, query(testStore, `$..book[0,1].price`) == `[8.95,12.99]`)
+	require(t, query(testStore, `$..book[:2].price`) == `[8.95,12.99]`)
+	require(t, query(testStore, `$..book[?@.isbn].title`) == `["Moby Dick","The Lord of the Rings"]`)
+	require(t, query(testStore, `$.store.book[?(@.price < 10)].title`) == `["Sayings of the Century","Moby Dick"]`)
+	require(t, query(testStore, `$..book[?@.price<10 && @.category=='fiction'].title`) == `["Moby Dick"]`)
+	require(t, query(testStore, `$..book[?!(@.price<10) || @.price == 8.95].price`) == `[8.95,12.99,22.99]`)
+	require(t, query(testStore, `$..book[?@.price > $.store.bicycle.price]`) == `[]`)
+	require(t, query(testStore, `$.store['bicycle']["color"]`) == `["red"]`)
+	require(t, query(testStore, `$`) == "["+testStore.JSON()+"]")
+
+	// Go scalars are leaves; structs and typed slices are still traversed
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	v := NewValue(Object{"i": int64(1), "n": json.Number("2"), "t": at, "s": struct{ X []int }{[]int{3}}})
+	require(t, query(v, `$..*`) == `[1,2,{"X":[3]},"2024-01-02T00:00:00Z",[3],3]`)
+	require(t, query(v, `$.t.*`) == `[]` && query(v, `$.s.X[0]`) == `[3]`)
+}
+
+func TestValue_Query_slices(t *testing.T) {
+	v := MustParse([]byte(`["a","b","c","d","e","f","g"]`))
+
+	require(t, query(v, `$[1:3]`) == `["b","c"]`)
+	require(t, query(v, `$[5:]`) == `["f","g"]`)
+	require(t, query(v, `$[1:5:2]`) == `["b","d"]`)
+	require(t, query(v, `$[5:1:-2]`) == `["f","d"]`)
+	require(t, query(v, `$[::-1]`) == `["g","f","e","d","c","b","a"]`)
+	require(t, query(v, `$[-2:]`) == `["f","g"]`)
+	require(t, query(v, `$[::0]`) == `[]`)
+}
+
+func TestValue_Query_functions(t *testing.T) {
+	v := MustParse([]byte(`[{"s":"abc","a":[1,2]},{"s":"ab\ncd","a":[]},{"s":"bc","a":{"x":1}}]`))
+
+	require(t, query(v, `$[?length(@.s) == 3].s`) == `["abc"]`)
+	require(t, query(v, `$[?length(@.a) > 0].s`) == `["abc","bc"]`)
+	require(t, query(v, `$[?count(@.a[*]) == 2].s`) == `["abc"]`)
+	require(t, query(v, `$[?match(@.s, 'a.c')].s`) == `["abc"]`)
+	require(t, query(v, `$[?search(@.s, 'b.')].s`) == `["abc","bc"]`)
+	require(t, query(v, `$[?value(@..x) == 1].s`) == `["bc"]`)
+	require(t, query(v, `$[?@.a == [1,2]]`) == `error`)
+
+	// the regexp cache is bounded and tells match() from search()
+	require(t, query(v, `$[?search(@.s, '^b')].s`) == `["bc"]` && query(v, `$[?match(@.s, 'b')].s`) == `[]`)
+	for i := range 2 * maxRegexpCache {
+		jpRegexp(strconv.Itoa(i), false)
+	}
+	require(t, len(jpRegexpCache) <= maxRegexpCache)
+}
+
+func TestCompileJSONPath_fail(t *testing.T) {
+	for _, expr := range []string{
+		``, `store`, `$.`, `$[`, `$[01]`, `$[-0]`, `$['a'`, `$..`, `$ `,
+		`$[?@.a == ]`, `$[?length(@.a)]`, `$[?match(@.a, 'x') == true]`,
+		`$[?@..a == 1]`, `$[?foo(@)]`, `$[?count(1) == 1]`, `$[?1]`,
+	} {
+		_, err := CompileJSONPath(expr)
+		require(t, err != nil)
+	}
+}
+
+func TestCompileJSONPath_equal(t *testing.T) {
+	v := MustParse([]byte(`[{"a":1,"b":"1"},{"a":null},{"b":2},{"a":{"x":[1]}}]`))
+
+	require(t, query(v, `$[?@.a == 1]`) == `[{"a":1,"b":"1"}]`)
+	require(t, query(v, `$[?@.b == '1']`) == `[{"a":1,"b":"1"}]`)
+	require(t, query(v, `$[?@.a == null]`) == `[{"a":null}]`)
+	require(t, query(v, `$[?@.a == @.c]`) == `[{"b":2}]`)
+	require(t, query(v, `$[?@.a == $[3].a]`) == `[{"a":{"x":[1]}}]`)
+	require(t, query(v, `$[?@.a != 1].b`) == `[2]`)
+}
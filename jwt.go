@@ -0,0 +1,278 @@
+package js
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrJWTMalformed    = errors.New("malformed token")
+	ErrJWTAlgorithm    = errors.New("unsupported algorithm or key")
+	ErrJWTSignature    = errors.New("invalid signature")
+	ErrJWTExpired      = errors.New("token is expired")
+	ErrJWTNotValidYet  = errors.New("token is not valid yet")
+	ErrJWTIssuedFuture = errors.New("token is issued in the future")
+	ErrJWTAudience     = errors.New("invalid audience")
+	ErrJWTIssuer       = errors.New("invalid issuer")
+)
+
+// JWTOptions configures the validation of ParseJWTWith.
+type JWTOptions struct {
+	// Algorithms lists the accepted "alg" values (all supported ones by default).
+	Algorithms []string
+
+	// Issuer, if set, must be equal to the "iss" claim.
+	Issuer string
+
+	// Audience, if set, must be the "aud" claim or one of its elements.
+	Audience string
+
+	// Leeway is the allowed clock skew for the "exp", "nbf" and "iat" claims.
+	Leeway time.Duration
+
+	// RequireExp rejects tokens without the "exp" claim.
+	RequireExp bool
+
+	// Now returns the current time (time.Now by default).
+	Now func() time.Time
+}
+
+// ParseJWT verifies the signature of a JWS compact token (a JWT) and validates
+// its "exp", "nbf" and "iat" claims. keyfunc returns the key to verify
+// the token with, chosen by the header (e.g. by "kid"):
+// []byte for HS256/384/512, *rsa.PublicKey for RS256, *ecdsa.PublicKey (P-256)
+// for ES256, ed25519.PublicKey for EdDSA.
+// If the signature is valid but a claim is not, the header and claims
+// are returned along with the error.
+func ParseJWT(token string, keyfunc func(header Object) (any, error)) (header, claims Object, err error) {
+	return ParseJWTWith(token, keyfunc, JWTOptions{})
+}
+
+// ParseJWTWith is ParseJWT with validation options.
+func ParseJWTWith(token string, keyfunc func(header Object) (any, error), opts JWTOptions) (header, claims Object, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, jwtErr(ErrJWTMalformed, "want 3 parts, got %d", len(parts))
+	}
+	if header, err = decodeJWTPart(parts[0]); err != nil {
+		return nil, nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, jwtErr(ErrJWTMalformed, "%v", err)
+	}
+	alg := header.GetStr("alg")
+	if opts.Algorithms != nil && !slices.Contains(opts.Algorithms, alg) {
+		return nil, nil, jwtErr(ErrJWTAlgorithm, "%q is not allowed", alg)
+	}
+	if header.Has("crit") { // no extensions are understood
+		return nil, nil, jwtErr(ErrJWTMalformed, "unsupported critical header %s", header.Get("crit").JSON())
+	}
+	key, err := keyfunc(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = verifyJWS(alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, nil, err
+	}
+	if claims, err = decodeJWTPart(parts[1]); err != nil {
+		return nil, nil, err
+	}
+	return header, claims, validateClaims(claims, opts)
+}
+
+func decodeJWTPart(s string) (Object, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, jwtErr(ErrJWTMalformed, "%v", err)
+	}
+	obj, err := ParseObject(data)
+	if err != nil || obj == nil {
+		return nil, jwtErr(ErrJWTMalformed, "not a JSON object")
+	}
+	return obj, nil
+}
+
+func jwtErr(err error, format string, args ...any) error {
+	return fmt.Errorf("js: jwt: %w: %s", err, fmt.Sprintf(format, args...))
+}
+
+func validateClaims(claims Object, opts JWTOptions) error {
+	now := time.Now()
+	if opts.Now != nil {
+		now = opts.Now()
+	}
+	for _, name := range []string{"exp", "nbf", "iat"} {
+		if claims.Has(name) && !claims.Get(name).IsNum() {
+			return jwtErr(ErrJWTMalformed, "claim %q is not a number", name)
+		}
+	}
+	switch {
+	case claims.Has("exp") && !now.Before(claims.GetTime("exp").Add(opts.Leeway)):
+		return jwtErr(ErrJWTExpired, "at %s", claims.GetTime("exp").UTC().Format(time.RFC3339))
+	case !claims.Has("exp") && opts.RequireExp:
+		return jwtErr(ErrJWTMalformed, "no \"exp\" claim")
+	case claims.Has("nbf") && now.Add(opts.Leeway).Before(claims.GetTime("nbf")):
+		return jwtErr(ErrJWTNotValidYet, "until %s", claims.GetTime("nbf").UTC().Format(time.RFC3339))
+	case claims.Has("iat") && now.Add(opts.Leeway).Before(claims.GetTime("iat")):
+		return jwtErr(ErrJWTIssuedFuture, "at %s", claims.GetTime("iat").UTC().Format(time.RFC3339))
+	case opts.Issuer != "" && claims.GetStr("iss") != opts.Issuer:
+		return jwtErr(ErrJWTIssuer, "%q", claims.GetStr("iss"))
+	case opts.Audience != "" && !hasAudience(claims.Get("aud"), opts.Audience):
+		return jwtErr(ErrJWTAudience, "%s", claims.Get("aud").JSON())
+	}
+	return nil
+}
+
+func hasAudience(aud Value, audience string) bool {
+	if aud.IsArray() {
+		for _, a := range aud.Array() {
+			if a == audience {
+				return true
+			}
+		}
+		return false
+	}
+	return aud.IsString() && aud.String() == audience
+}
+
+// SignJWT returns a JWS compact token with the header and claims, signed with key:
+// []byte for HS256/384/512, *rsa.PrivateKey for RS256, *ecdsa.PrivateKey (P-256)
+// for ES256, ed25519.PrivateKey for EdDSA. If the header has no "alg",
+// it is chosen by the key (HS256, RS256, ES256 or EdDSA); "typ" defaults to "JWT".
+func SignJWT(header, claims Object, key any) (string, error) {
+	header = header.Clone().Set("typ", Or(header.get("typ"), "JWT"))
+	if !header.Has("alg") {
+		switch key.(type) {
+		case []byte:
+			header["alg"] = "HS256"
+		case *rsa.PrivateKey:
+			header["alg"] = "RS256"
+		case *ecdsa.PrivateKey:
+			header["alg"] = "ES256"
+		case ed25519.PrivateKey:
+			header["alg"] = "EdDSA"
+		default:
+			return "", jwtErr(ErrJWTAlgorithm, "key of type %T", key)
+		}
+	}
+	if claims == nil {
+		claims = Object{}
+	}
+	input := base64.RawURLEncoding.EncodeToString(header.Bytes()) + "." +
+		base64.RawURLEncoding.EncodeToString(claims.Bytes())
+	sig, err := signJWS(header.GetStr("alg"), key, input)
+	if err != nil {
+		return "", err
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func jwsHash(alg string) (crypto.Hash, func() hash.Hash) {
+	switch alg {
+	case "HS256", "RS256", "ES256":
+		return crypto.SHA256, sha256.New
+	case "HS384":
+		return crypto.SHA384, sha512.New384
+	case "HS512":
+		return crypto.SHA512, sha512.New
+	}
+	return 0, nil
+}
+
+func digest(h func() hash.Hash, input string) []byte {
+	d := h()
+	d.Write([]byte(input))
+	return d.Sum(nil)
+}
+
+func signJWS(alg string, key any, input string) ([]byte, error) {
+	ch, h := jwsHash(alg)
+	switch k := key.(type) {
+	case []byte:
+		if strings.HasPrefix(alg, "HS") && h != nil && len(k) > 0 {
+			mac := hmac.New(h, k)
+			mac.Write([]byte(input))
+			return mac.Sum(nil), nil
+		}
+	case *rsa.PrivateKey:
+		if alg == "RS256" {
+			return rsa.SignPKCS1v15(rand.Reader, k, ch, digest(h, input))
+		}
+	case *ecdsa.PrivateKey:
+		if alg == "ES256" && k.Curve == elliptic.P256() {
+			r, s, err := ecdsa.Sign(rand.Reader, k, digest(h, input))
+			if err != nil {
+				return nil, err
+			}
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+			return sig, nil
+		}
+	case ed25519.PrivateKey:
+		if alg == "EdDSA" && len(k) == ed25519.PrivateKeySize {
+			return ed25519.Sign(k, []byte(input)), nil
+		}
+	}
+	return nil, jwtKeyErr(alg, key)
+}
+
+// verifyJWS checks the signature; the key type must match the algorithm,
+// so that e.g. an RSA public key is never used as an HMAC secret.
+func verifyJWS(alg string, key any, input string, sig []byte) error {
+	ch, h := jwsHash(alg)
+	var ok bool
+	switch k := key.(type) {
+	case []byte:
+		if !strings.HasPrefix(alg, "HS") || h == nil || len(k) == 0 {
+			return jwtKeyErr(alg, key)
+		}
+		mac := hmac.New(h, k)
+		mac.Write([]byte(input))
+		ok = hmac.Equal(sig, mac.Sum(nil))
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return jwtKeyErr(alg, key)
+		}
+		ok = rsa.VerifyPKCS1v15(k, ch, digest(h, input), sig) == nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || k.Curve != elliptic.P256() {
+			return jwtKeyErr(alg, key)
+		}
+		if len(sig) == 64 {
+			r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+			ok = ecdsa.Verify(k, digest(h, input), r, s)
+		}
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return jwtKeyErr(alg, key)
+		}
+		ok = len(k) == ed25519.PublicKeySize && ed25519.Verify(k, []byte(input), sig)
+	default:
+		return jwtKeyErr(alg, key)
+	}
+	if !ok {
+		return jwtErr(ErrJWTSignature, "%s", alg)
+	}
+	return nil
+}
+
+func jwtKeyErr(alg string, key any) error {
+	return jwtErr(ErrJWTAlgorithm, "%q with key of type %T", alg, key)
+}
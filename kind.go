@@ -0,0 +1,179 @@
+package js
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Kind is the type of a value as seen by JSON.
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindBool
+	KindNumber
+	KindString
+	KindObject
+	KindArray
+	KindBinary // []byte, encoded as a base64 string
+	KindTime   // time.Time, encoded as an RFC 3339 string
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "boolean"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindObject:
+		return "object"
+	case KindArray:
+		return "array"
+	case KindBinary:
+		return "binary"
+	case KindTime:
+		return "time"
+	}
+	return "unknown"
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// KindOf returns the kind of the Go value v as it would be encoded to JSON.
+// Plain values are classified by their type without encoding; only types with
+// a custom MarshalJSON method are encoded to find out what they produce.
+func KindOf(v any) Kind {
+	switch val := v.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case string:
+		return KindString
+	case float64, int, int64, json.Number:
+		return KindNumber
+	case map[string]any:
+		if val == nil {
+			return KindNull
+		}
+		return KindObject
+	case Object:
+		if val == nil {
+			return KindNull
+		}
+		return KindObject
+	case []any:
+		if val == nil {
+			return KindNull
+		}
+		return KindArray
+	case Array:
+		if val == nil {
+			return KindNull
+		}
+		return KindArray
+	case *OrderedObject:
+		if val == nil {
+			return KindNull
+		}
+		return KindObject
+	case Value:
+		return KindOf(val.val)
+	case json.RawMessage:
+		return rawKind(val)
+	case []byte:
+		return KindBinary
+	case time.Time:
+		return KindTime
+	}
+	return reflectKind(reflect.ValueOf(v))
+}
+
+func reflectKind(rv reflect.Value) Kind {
+	if !rv.IsValid() {
+		return KindNull
+	}
+	t := rv.Type()
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return KindNull
+		}
+	}
+	switch {
+	case t.Implements(jsonMarshalerType):
+		data, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return KindNull
+		}
+		return rawKind(data)
+	case t.Implements(textMarshalerType):
+		return KindString
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return KindNumber
+	case reflect.String:
+		return KindString
+	case reflect.Map, reflect.Struct:
+		return KindObject
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return KindBinary
+		}
+		return KindArray
+	case reflect.Array:
+		return KindArray
+	case reflect.Pointer, reflect.Interface:
+		return reflectKind(rv.Elem())
+	}
+	return KindNull // unsupported by JSON: channels, funcs, complex numbers
+}
+
+// rawKind classifies encoded JSON by its first byte.
+func rawKind(data []byte) Kind {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return KindNull
+	}
+	switch data[0] {
+	case '{':
+		return KindObject
+	case '[':
+		return KindArray
+	case '"':
+		return KindString
+	case 't', 'f':
+		return KindBool
+	case 'n':
+		return KindNull
+	}
+	return KindNumber
+}
+
+// Kind returns the kind of the value.
+func (v Value) Kind() Kind {
+	return KindOf(v.val)
+}
+
+func (v Value) IsString() bool {
+	return v.Kind() == KindString
+}
+
+func (v Value) IsBool() bool {
+	return v.Kind() == KindBool
+}
@@ -0,0 +1,47 @@
+package js
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestValue_Kind(t *testing.T) {
+	v := MustParse([]byte(`{"n":null,"b":true,"x":1.5,"s":"str","o":{},"a":[]}`))
+
+	require(t, v.Kind() == KindObject)
+	require(t, v.Path("n").Kind() == KindNull)
+	require(t, v.Path("missing").Kind() == KindNull)
+	require(t, v.Path("b").Kind() == KindBool && v.Path("b").IsBool())
+	require(t, v.Path("x").Kind() == KindNumber)
+	require(t, v.Path("s").Kind() == KindString && v.Path("s").IsString())
+	require(t, v.Path("o").Kind() == KindObject && v.Path("o").IsObject())
+	require(t, v.Path("a").Kind() == KindArray && v.Path("a").IsArray())
+	require(t, KindArray.String() == "array")
+}
+
+func TestKindOf(t *testing.T) {
+	type user struct{ Name string }
+	var nilObj Object
+	var nilPtr *user
+
+	require(t, KindOf(user{"a"}) == KindObject)
+	require(t, KindOf(&user{"a"}) == KindObject)
+	require(t, KindOf(nilPtr) == KindNull)
+	require(t, KindOf(nilObj) == KindNull)
+	require(t, KindOf(map[string]int{}) == KindObject)
+	require(t, KindOf([]string{"a"}) == KindArray)
+	require(t, KindOf([2]int{}) == KindArray)
+	require(t, KindOf([]byte("abc")) == KindBinary)
+	require(t, KindOf(time.Now()) == KindTime)
+	require(t, KindOf(uint8(1)) == KindNumber)
+	require(t, KindOf(json.Number("12")) == KindNumber)
+	require(t, KindOf(json.RawMessage(` [1]`)) == KindArray)
+	require(t, KindOf(big.NewInt(5)) == KindNumber) // custom MarshalJSON
+	require(t, KindOf(NewValue("x")) == KindString)
+
+	require(t, !NewValue([]byte("abc")).IsArray())
+	require(t, !NewValue(time.Now()).IsObject())
+	require(t, NewValue(user{}).IsObject())
+}
@@ -0,0 +1,130 @@
+package js
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+)
+
+// LineReaderOptions configures NewLineReaderWith.
+type LineReaderOptions struct {
+	// ParseOptions are applied to every line.
+	ParseOptions
+
+	// Tolerant skips malformed lines: they are reported as *SyntaxError
+	// (with the line number of the input) and reading continues.
+	// Otherwise the first malformed line ends the input.
+	Tolerant bool
+}
+
+// LineReader reads newline-delimited JSON (NDJSON, JSON Lines) one value
+// at a time, holding only the current line in memory. Blank lines are skipped.
+type LineReader struct {
+	r      *bufio.Reader
+	opts   LineReaderOptions
+	line   int
+	offset int64
+	err    error // sticky error returned by all subsequent calls of Next
+}
+
+// NewLineReader creates a reader of newline-delimited JSON values.
+func NewLineReader(r io.Reader) *LineReader {
+	return NewLineReaderWith(r, LineReaderOptions{})
+}
+
+// NewLineReaderWith creates a reader of newline-delimited JSON values.
+func NewLineReaderWith(r io.Reader, opts LineReaderOptions) *LineReader {
+	return &LineReader{r: bufio.NewReader(r), opts: opts}
+}
+
+// Line returns the number of the last line read (1-based).
+func (lr *LineReader) Line() int {
+	return lr.line
+}
+
+// Next returns the next value. Malformed lines are reported as *SyntaxError.
+// At the end of the input it returns io.EOF.
+func (lr *LineReader) Next() (Value, error) {
+	for lr.err == nil {
+		data, err := lr.r.ReadBytes('\n')
+		if err != nil {
+			lr.err = err
+			if len(data) == 0 {
+				break
+			}
+		}
+		lr.line++
+		start := lr.offset
+		lr.offset += int64(len(data))
+		trimmed := bytes.TrimLeft(data, " \t\r\n")
+		if len(bytes.TrimSpace(trimmed)) == 0 {
+			continue
+		}
+		v, err := Parse(trimmed, lr.opts.ParseOptions)
+		if err != nil {
+			err = lr.lineError(data, start, len(data)-len(trimmed), err)
+			if !lr.opts.Tolerant {
+				lr.err = err
+			}
+			return Value{}, err
+		}
+		return v, nil
+	}
+	return Value{}, lr.err
+}
+
+// lineError reports the position of a parse error in the input.
+func (lr *LineReader) lineError(data []byte, start int64, lead int, err error) error {
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		return err
+	}
+	res := newSyntaxError(data, int64(lead)+se.Offset, "%s", se.Msg)
+	res.Line = lr.line
+	res.Offset += start
+	return res
+}
+
+// All returns an iterator over the values. The iteration stops at the end
+// of the input or after the first error unless the reader is tolerant.
+func (lr *LineReader) All() iter.Seq2[Value, error] {
+	return func(yield func(Value, error) bool) {
+		for {
+			v, err := lr.Next()
+			if err == io.EOF || !yield(v, err) || err != nil && err == lr.err {
+				return
+			}
+		}
+	}
+}
+
+// LineWriter writes newline-delimited JSON (NDJSON, JSON Lines).
+// Output is buffered: call Flush when done.
+type LineWriter struct {
+	w *bufio.Writer
+}
+
+// NewLineWriter creates a buffered writer of newline-delimited JSON values.
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{bufio.NewWriter(w)}
+}
+
+// Write encodes v as a single line of JSON.
+func (lw *LineWriter) Write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err = lw.w.Write(data); err != nil {
+		return err
+	}
+	return lw.w.WriteByte('\n')
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (lw *LineWriter) Flush() error {
+	return lw.w.Flush()
+}
@@ -0,0 +1,83 @@
+package js
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineReader(t *testing.T) {
+	src := "{\"id\":1}\n\n  {\"id\":2}\r\n[3]\n\"x\""
+	var ids []string
+	for v, err := range NewLineReader(strings.NewReader(src)).All() {
+		require(t, err == nil)
+		ids = append(ids, v.JSON())
+	}
+	require(t, strings.Join(ids, ",") == `{"id":1},{"id":2},[3],"x"`)
+
+	lr := NewLineReader(strings.NewReader(`{"a":1}` + "\n"))
+	v, err := lr.Next()
+	require(t, err == nil && v.Path("a").Int() == 1 && lr.Line() == 1)
+	_, err = lr.Next()
+	require(t, err == io.EOF)
+	_, err = lr.Next()
+	require(t, err == io.EOF)
+}
+
+func TestLineReader_Errors(t *testing.T) {
+	src := "{\"id\":1}\n  {\"id\":}\n{\"id\":3}\nnope\n"
+
+	var n int
+	var errs []error
+	for _, err := range NewLineReader(strings.NewReader(src)).All() {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			n++
+		}
+	}
+	var se *SyntaxError
+	require(t, n == 1 && len(errs) == 1)
+	require(t, errors.As(errs[0], &se) && se.Line == 2 && se.Column == 9 && se.Offset == 17)
+
+	n, errs = 0, nil
+	lr := NewLineReaderWith(strings.NewReader(src), LineReaderOptions{Tolerant: true})
+	for _, err := range lr.All() {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			n++
+		}
+	}
+	require(t, n == 2 && len(errs) == 2)
+	require(t, errors.As(errs[1], &se) && se.Line == 4 && se.Column == 2)
+}
+
+func TestLineReader_Options(t *testing.T) {
+	lr := NewLineReaderWith(strings.NewReader(`{"id":12345678901234567890}`), LineReaderOptions{
+		ParseOptions: ParseOptions{UseNumber: true},
+	})
+	v, err := lr.Next()
+	require(t, err == nil && v.Path("id").String() == "12345678901234567890")
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lw := NewLineWriter(&buf)
+
+	require(t, lw.Write(Object{"a": "x\ny"}) == nil)
+	require(t, lw.Write(Array{1, 2}) == nil)
+	require(t, lw.Write(func() {}) != nil)
+	require(t, buf.Len() == 0)
+	require(t, lw.Flush() == nil)
+	require(t, buf.String() == "{\"a\":\"x\\ny\"}\n[1,2]\n")
+
+	var vals []string
+	for v, err := range NewLineReader(&buf).All() {
+		require(t, err == nil)
+		vals = append(vals, v.JSON())
+	}
+	require(t, len(vals) == 2)
+}
@@ -0,0 +1,123 @@
+package js
+
+// ArrayMergeStrategy defines how DeepMergeWith combines two arrays.
+type ArrayMergeStrategy int
+
+const (
+	ArrayReplace      ArrayMergeStrategy = iota // the later array replaces the earlier one
+	ArrayConcat                                 // elements of the later array are appended
+	ArrayMergeByIndex                           // elements with the same index are merged
+	ArrayMergeByKey                             // objects with the same MergeOptions.Key value are merged
+)
+
+// MergeOptions configures DeepMergeWith.
+type MergeOptions struct {
+	Arrays ArrayMergeStrategy
+	Key    string // element key for ArrayMergeByKey, e.g. "id"
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to the object in place:
+// null members delete keys and nested objects are patched recursively.
+func (obj Object) MergePatch(patch Object) Object {
+	if obj == nil {
+		obj = Object{}
+	}
+	for k, v := range patch {
+		switch p, ok := mergeObject(v); {
+		case isNil(v):
+			delete(obj, k)
+		case ok:
+			target, _ := mergeObject(obj[k])
+			obj[k] = objectNode(obj[k], target.MergePatch(p))
+		default:
+			obj[k] = deepClone(v)
+		}
+	}
+	return obj
+}
+
+// DeepMerge merges other objects into the object recursively:
+// nested objects are merged, other values (including arrays) are replaced.
+func (obj Object) DeepMerge(others ...Object) Object {
+	return obj.DeepMergeWith(MergeOptions{}, others...)
+}
+
+// DeepMergeWith merges other objects into the object recursively,
+// combining arrays according to opts.Arrays.
+func (obj Object) DeepMergeWith(opts MergeOptions, others ...Object) Object {
+	if obj == nil {
+		obj = Object{}
+	}
+	for _, other := range others {
+		for k, v := range other {
+			if cur, ok := obj[k]; ok {
+				obj[k] = mergeValues(cur, v, opts)
+			} else {
+				obj[k] = deepClone(v)
+			}
+		}
+	}
+	return obj
+}
+
+func mergeValues(a, b any, opts MergeOptions) any {
+	if oa, ok := mergeObject(a); ok {
+		if ob, ok := mergeObject(b); ok {
+			return objectNode(a, oa.DeepMergeWith(opts, ob))
+		}
+	}
+	aa, ok1 := mergeArray(a)
+	bb, ok2 := mergeArray(b)
+	if !ok1 || !ok2 {
+		return deepClone(b)
+	}
+	switch opts.Arrays {
+	case ArrayConcat:
+		return append(aa[:len(aa):len(aa)], deepCloneArray(bb)...)
+	case ArrayMergeByIndex:
+		for i, v := range bb {
+			if i < len(aa) {
+				aa[i] = mergeValues(aa[i], v, opts)
+			} else {
+				aa = append(aa, deepClone(v))
+			}
+		}
+		return aa
+	case ArrayMergeByKey:
+		for _, v := range bb {
+			i := -1
+			if ov, ok := mergeObject(v); ok && ov.Has(opts.Key) {
+				i = aa.IndexOfFn(func(e Value) bool {
+					oe, ok := mergeObject(e.val)
+					return ok && oe.Has(opts.Key) && deepEqual(oe[opts.Key], ov[opts.Key])
+				})
+			}
+			if i >= 0 {
+				aa[i] = mergeValues(aa[i], v, opts)
+			} else {
+				aa = append(aa, deepClone(v))
+			}
+		}
+		return aa
+	}
+	return deepClone(b)
+}
+
+// mergeObject returns v as an Object if it is a JSON object.
+func mergeObject(v any) (Object, bool) {
+	switch v.(type) {
+	case nil, []any, Array:
+		return nil, false
+	}
+	return asObject(v)
+}
+
+func mergeArray(v any) (Array, bool) {
+	switch v := v.(type) {
+	case []any:
+		return v, true
+	case Array:
+		return v, true
+	}
+	return nil, false
+}
@@ -0,0 +1,50 @@
+package js
+
+import "testing"
+
+func TestObject_MergePatch(t *testing.T) {
+	// examples from RFC 7386, appendix A
+	for _, c := range [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		obj := MustParseObject([]byte(c[0]))
+
+		res := obj.MergePatch(MustParseObject([]byte(c[1])))
+
+		require(t, res.String() == c[2])
+	}
+	require(t, Object(nil).MergePatch(Object{"a": 1}).String() == `{"a":1}`)
+}
+
+func TestObject_DeepMerge(t *testing.T) {
+	base := MustParseObject([]byte(`{"db":{"host":"localhost","port":5432},"tags":["a"]}`))
+	over := MustParseObject([]byte(`{"db":{"port":6432,"user":"app"},"tags":["b"],"debug":null}`))
+
+	res := base.DeepMerge(over)
+	over.GetObj("db")["port"] = 0 // result must not share nested values
+
+	require(t, res.String() == `{"db":{"host":"localhost","port":6432,"user":"app"},"debug":null,"tags":["b"]}`)
+}
+
+func TestObject_DeepMergeWith(t *testing.T) {
+	base := `{"items":[{"id":1,"v":1,"x":true},{"id":2,"v":2}]}`
+	over := `{"items":[{"id":2,"v":20},{"id":3,"v":3}]}`
+
+	merge := func(opts MergeOptions) string {
+		return MustParseObject([]byte(base)).DeepMergeWith(opts, MustParseObject([]byte(over))).String()
+	}
+
+	require(t, merge(MergeOptions{Arrays: ArrayReplace}) == `{"items":[{"id":2,"v":20},{"id":3,"v":3}]}`)
+	require(t, merge(MergeOptions{Arrays: ArrayConcat}) == `{"items":[{"id":1,"v":1,"x":true},{"id":2,"v":2},{"id":2,"v":20},{"id":3,"v":3}]}`)
+	require(t, merge(MergeOptions{Arrays: ArrayMergeByIndex}) == `{"items":[{"id":2,"v":20,"x":true},{"id":3,"v":3}]}`)
+	require(t, merge(MergeOptions{Arrays: ArrayMergeByKey, Key: "id"}) == `{"items":[{"id":1,"v":1,"x":true},{"id":2,"v":20},{"id":3,"v":3}]}`)
+}
@@ -0,0 +1,115 @@
+package js
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Number returns the numeric literal of the value (e.g. as kept by
+// ParseOptions.UseNumber), or an empty string if it is not a number.
+func (v Value) Number() json.Number {
+	switch val := v.val.(type) {
+	case json.Number:
+		return val
+	case string:
+		if parseRat(val) != nil {
+			return json.Number(val)
+		}
+		return ""
+	}
+	if IsNum(v.val) {
+		return json.Number(v.String())
+	}
+	return ""
+}
+
+// BigInt returns the value as an arbitrary-precision integer (fractions are
+// truncated), or nil if it is not a number.
+func (v Value) BigInt() *big.Int {
+	if r := toRat(v.val); r != nil {
+		return new(big.Int).Quo(r.Num(), r.Denom())
+	}
+	return nil
+}
+
+// BigFloat returns the value as an arbitrary-precision float with enough
+// precision to hold all digits of the literal, or nil if it is not a number.
+func (v Value) BigFloat() *big.Float {
+	s := string(v.Number())
+	if s == "" {
+		return nil
+	}
+	f, _, err := big.ParseFloat(s, 10, uint(max(64, len(s)*4)), big.ToNearestEven)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// BigRat returns the exact decimal value of the number (e.g. a money amount
+// like "0.1" without binary rounding), or nil if it is not a number.
+func (v Value) BigRat() *big.Rat {
+	return toRat(v.val)
+}
+
+// toRat converts a number (or numeric string) to an exact rational.
+func toRat(v any) *big.Rat {
+	switch val := v.(type) {
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil
+		}
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return new(big.Rat).SetInt64(int64(val))
+		}
+		// use the shortest decimal representation, as the JSON literal would
+		r, _ := new(big.Rat).SetString(NewValue(val).String())
+		return r
+	case float32:
+		return toRat(float64(val))
+	case uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(val))
+	case uint:
+		return toRat(uint64(val))
+	case json.Number:
+		return parseRat(string(val))
+	case string:
+		return parseRat(strings.TrimSpace(val))
+	}
+	if IsInt(v) {
+		return new(big.Rat).SetInt64(NewValue(v).Int64())
+	}
+	return nil
+}
+
+func parseRat(s string) *big.Rat {
+	if !jsonNumberRx.MatchString(s) {
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// isFloatExact reports whether the number v is represented exactly by float64.
+func isFloatExact(v any) bool {
+	switch val := v.(type) {
+	case float64, float32, int8, uint8, int16, uint16, int32, uint32:
+		return true
+	case int:
+		return val > -1<<53 && val < 1<<53
+	case int64:
+		return val > -1<<53 && val < 1<<53
+	case uint:
+		return val < 1<<53
+	case uint64:
+		return val < 1<<53
+	case json.Number:
+		return len(val) < 16 && !strings.ContainsAny(string(val), ".eE")
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package js
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse_UseNumber(t *testing.T) {
+	data := []byte(`{"amount":0.1,"big":9007199254740993,"e":1e3,"id":12345678901234567891,"neg":-9007199254740993}`)
+	opts := ParseOptions{UseNumber: true}
+
+	lossy := MustParse(data)
+	v := MustParse(data, opts)
+	obj := MustParseObject(data, opts)
+
+	require(t, lossy.Path("big").Int64() != 9007199254740993)
+	require(t, v.Path("big").Int64() == 9007199254740993)
+	require(t, v.Path("neg").Int64() == -9007199254740993)
+	require(t, v.Path("id").Uint64() == 12345678901234567891)
+	require(t, v.Path("e").Int64() == 1000)
+	require(t, v.Path("amount").Float64() == 0.1)
+	require(t, v.Path("amount").IsNum())
+	require(t, obj.GetUint64Path("id") == 12345678901234567891)
+	require(t, v.JSON() == string(data))
+	require(t, v.Path("id").String() == "12345678901234567891")
+
+	_, err := Parse([]byte(`{"a":1} x`), opts)
+	require(t, err != nil)
+	_, err = Parse([]byte(`{"a":1`), opts)
+	require(t, err != nil)
+}
+
+func TestParseFile_UseNumber(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "a.json")
+	require(t, os.WriteFile(filename, []byte(`{"id":9007199254740993}`), 0600) == nil)
+
+	v, err := ParseFile(filename, ParseOptions{UseNumber: true})
+	require(t, err == nil && v.Path("id").Int64() == 9007199254740993)
+}
+
+func TestValue_BigNumbers(t *testing.T) {
+	v := MustParse([]byte(`{"id":123456789012345678901234567890,"amount":19.99,"f":2.5,"s":"x"}`), ParseOptions{UseNumber: true})
+
+	bi, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require(t, v.Path("id").BigInt().Cmp(bi) == 0)
+	require(t, v.Path("amount").BigRat().Cmp(big.NewRat(1999, 100)) == 0)
+	require(t, v.Path("f").BigInt().Int64() == 2)
+	require(t, v.Path("id").BigFloat().Text('f', 0) == "123456789012345678901234567890")
+	require(t, NewValue(int64(-5)).BigInt().Int64() == -5)
+	require(t, NewValue(0.1).BigRat().Cmp(big.NewRat(1, 10)) == 0)
+	require(t, v.Path("s").BigInt() == nil)
+	require(t, v.Path("s").BigRat() == nil)
+	require(t, v.Path("s").Number() == "")
+	require(t, v.Path("amount").Number() == "19.99")
+}
+
+func TestCmp_Numbers(t *testing.T) {
+	v := MustParse([]byte(`[9007199254740993,9007199254740992,0.1]`), ParseOptions{UseNumber: true})
+	a, b := v.Array()[0], v.Array()[1]
+
+	require(t, Cmp(a, b) == 1)
+	require(t, Cmp(b, a) == -1)
+	require(t, Cmp(a, int64(9007199254740993)) == 0)
+	require(t, Cmp(uint64(1<<63), int64(1<<62)) == 1)
+	require(t, Cmp(v.Array()[2], 0.1) == 0)
+	require(t, Cmp(1, 2.5) == -1)
+	require(t, !NewValue(v.Array()[0]).Empty())
+}
@@ -6,13 +6,16 @@ import (
 	"io"
 	"maps"
 	"net/url"
+	"reflect"
 	"sort"
 	"time"
 )
 
 type Object map[string]any
 
-// NewObject creates a new Object from any Go value.
+// NewObject creates a new Object from any Go value. Structs are converted
+// by their json tags, like json.Marshal does, but without encoding:
+// time.Time, []byte and integer fields keep their types.
 func NewObject(v any) Object {
 	return must(toObject(v))
 }
@@ -33,11 +36,17 @@ func toObject(v any) (Object, error) {
 	case Value:
 		return toObject(val.val)
 	}
-	data, err := json.Marshal(v)
+	res, err := encodeTree(v)
 	if err != nil {
 		return nil, err
 	}
-	return ParseObject(data)
+	switch res := res.(type) {
+	case map[string]any:
+		return res, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, &json.UnmarshalTypeError{Value: jsonKind(res).String(), Type: reflect.TypeFor[Object]()}
 }
 
 // String converts the entire object to a JSON string.
@@ -84,12 +93,17 @@ func (obj Object) Delete(name string) Object {
 	return obj
 }
 
-// Clone creates a clone of the object.
+// Clone creates a shallow clone of the object: nested objects and arrays are shared.
 func (obj Object) Clone() Object {
 	return maps.Clone(obj)
 }
 
-// Extend merges the object with other objects.
+// DeepClone creates a clone of the object with all nested objects and arrays copied.
+func (obj Object) DeepClone() Object {
+	return deepCloneObject(obj)
+}
+
+// Extend merges the object with other objects (shallow, see DeepMerge).
 func (obj Object) Extend(o ...Object) Object {
 	if obj == nil {
 		obj = Object{}
@@ -195,7 +209,7 @@ func (obj Object) Keys() (keys []string) {
 
 // MarshalTo unmarshals the object into another structure.
 func (obj Object) MarshalTo(v any) error {
-	return json.Unmarshal(obj.Bytes(), v)
+	return decodeInto(obj, v)
 }
 
 // Encode converts the object to bytes (JSON) for encoding.
@@ -209,6 +223,13 @@ func (obj *Object) Decode(data []byte) (err error) {
 	return json.Unmarshal(data, &obj)
 }
 
+// DecodeTo stores the object into v (a pointer to a struct, map etc.) as
+// json.Unmarshal would store its JSON, without encoding it; it is the reverse
+// of NewObject. Decode(data []byte) is taken by the JSON byte decoder.
+func (obj Object) DecodeTo(v any) error {
+	return decodeInto(obj, v)
+}
+
 // URLValues converts the object to url.Values.
 func (obj Object) URLValues() (values url.Values) {
 	values = make(url.Values, len(obj))
@@ -221,22 +242,22 @@ func (obj Object) URLValues() (values url.Values) {
 }
 
 // ParseObject parses the object from bytes (JSON).
-func ParseObject(data []byte) (obj Object, err error) {
+func ParseObject(data []byte, opts ...ParseOptions) (obj Object, err error) {
 	if data = bytes.TrimSpace(data); len(data) > 0 {
-		err = json.Unmarshal(data, &obj)
+		err = unmarshal(data, &obj, parseOptions(opts))
 	}
 	return
 }
 
 // MustParseObject parses the object from bytes (JSON) and panics on error.
-func MustParseObject(data []byte) Object {
-	return must(ParseObject(data))
+func MustParseObject(data []byte, opts ...ParseOptions) Object {
+	return must(ParseObject(data, opts...))
 }
 
 // ReadObject reads the object from io.Reader.
-func ReadObject(r io.Reader) (obj Object, err error) {
+func ReadObject(r io.Reader, opts ...ParseOptions) (obj Object, err error) {
 	defer catch(&err)
-	return ParseObject(readAll(r))
+	return ParseObject(readInput(r, parseOptions(opts)), opts...)
 }
 
 // ObjectFromURLValues converts url.Values to an object.
@@ -0,0 +1,374 @@
+package js
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"time"
+)
+
+// OrderedObject is a JSON object that keeps its keys in insertion (or parse)
+// order, so that documents can be round-tripped with the original key order.
+// Nested objects of a parsed OrderedObject are *OrderedObject as well.
+// The zero value is an empty object ready to use.
+type OrderedObject struct {
+	keys []string
+	vals map[string]any
+}
+
+// NewOrderedObject creates an object from key-value pairs: key1, value1, key2, value2, ...
+func NewOrderedObject(kv ...any) *OrderedObject {
+	obj := &OrderedObject{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		obj.Set(ToStr(kv[i]), kv[i+1])
+	}
+	return obj
+}
+
+// ToOrderedObject converts obj to an OrderedObject with keys in sorted order;
+// nested objects are converted too.
+func ToOrderedObject(obj Object) *OrderedObject {
+	if obj == nil {
+		return nil
+	}
+	res := &OrderedObject{}
+	for _, k := range obj.Keys() {
+		res.Set(k, toOrdered(obj[k]))
+	}
+	return res
+}
+
+func toOrdered(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return ToOrderedObject(val)
+	case Object:
+		return ToOrderedObject(val)
+	case []any:
+		return toOrderedArray(val)
+	case Array:
+		return toOrderedArray(val)
+	}
+	return v
+}
+
+func toOrderedArray(arr []any) Array {
+	res := make(Array, len(arr))
+	for i, v := range arr {
+		res[i] = toOrdered(v)
+	}
+	return res
+}
+
+// ParseOrderedObject parses the object from bytes (JSON) keeping the key order.
+func ParseOrderedObject(data []byte) (*OrderedObject, error) {
+	obj := &OrderedObject{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Len returns the number of key-value pairs in the object.
+func (obj *OrderedObject) Len() int {
+	if obj == nil {
+		return 0
+	}
+	return len(obj.keys)
+}
+
+// Keys returns all keys of the object in insertion order.
+func (obj *OrderedObject) Keys() []string {
+	if obj == nil {
+		return nil
+	}
+	return slices.Clone(obj.keys)
+}
+
+// All returns an iterator over the key-value pairs in insertion order.
+func (obj *OrderedObject) All() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for _, k := range obj.Keys() {
+			if !yield(k, obj.Get(k)) {
+				return
+			}
+		}
+	}
+}
+
+// Has checks if the object contains the given key.
+func (obj *OrderedObject) Has(key string) bool {
+	if obj == nil {
+		return false
+	}
+	_, ok := obj.vals[key]
+	return ok
+}
+
+func (obj *OrderedObject) get(name string) any {
+	if obj == nil {
+		return nil
+	}
+	return obj.vals[name]
+}
+
+// Get retrieves the value for the given key as a Value.
+func (obj *OrderedObject) Get(name string) Value {
+	return Value{obj.get(name)}
+}
+
+// GetBool retrieves the boolean value by key.
+func (obj *OrderedObject) GetBool(name string) bool {
+	return obj.Get(name).Bool()
+}
+
+// GetStr retrieves the string value by key.
+func (obj *OrderedObject) GetStr(name string) string {
+	return obj.Get(name).String()
+}
+
+// GetNum retrieves the numeric value (float64) by key.
+func (obj *OrderedObject) GetNum(name string) float64 {
+	return obj.Get(name).Float64()
+}
+
+// GetInt retrieves the integer value (int) by key.
+func (obj *OrderedObject) GetInt(name string) int {
+	return obj.Get(name).Int()
+}
+
+// GetInt64 retrieves the 64-bit integer value (int64) by key.
+func (obj *OrderedObject) GetInt64(name string) int64 {
+	return obj.Get(name).Int64()
+}
+
+// GetUint64 retrieves the unsigned 64-bit integer value (uint64) by key.
+func (obj *OrderedObject) GetUint64(name string) uint64 {
+	return obj.Get(name).Uint64()
+}
+
+// GetTime retrieves the time value (time.Time) by key.
+func (obj *OrderedObject) GetTime(name string) time.Time {
+	return obj.Get(name).Time()
+}
+
+// GetObj retrieves the nested ordered object by key.
+// A nested Object is converted with sorted keys.
+func (obj *OrderedObject) GetObj(name string) *OrderedObject {
+	return obj.Get(name).OrderedObject()
+}
+
+// GetArr retrieves the array by key.
+func (obj *OrderedObject) GetArr(name string) Array {
+	return obj.Get(name).Array()
+}
+
+// Set adds a key-value pair at the end of the object,
+// or updates the value of an existing key keeping its position.
+func (obj *OrderedObject) Set(name string, v any) *OrderedObject {
+	if obj.vals == nil {
+		obj.vals = map[string]any{}
+	}
+	if _, ok := obj.vals[name]; !ok {
+		obj.keys = append(obj.keys, name)
+	}
+	obj.vals[name] = v
+	return obj
+}
+
+// Delete removes the specified key-value pair from the object.
+func (obj *OrderedObject) Delete(name string) *OrderedObject {
+	if obj.Has(name) {
+		delete(obj.vals, name)
+		obj.keys = slices.DeleteFunc(obj.keys, func(k string) bool { return k == name })
+	}
+	return obj
+}
+
+// sync updates the key order after the map was changed directly (see asObject):
+// deleted keys are dropped and new keys are appended in sorted order.
+func (obj *OrderedObject) sync() {
+	keys := slices.DeleteFunc(obj.keys, func(k string) bool {
+		_, ok := obj.vals[k]
+		return !ok
+	})
+	if len(keys) < len(obj.vals) {
+		known := make(map[string]bool, len(keys))
+		for _, k := range keys {
+			known[k] = true
+		}
+		var added []string
+		for k := range obj.vals {
+			if !known[k] {
+				added = append(added, k)
+			}
+		}
+		slices.Sort(added)
+		keys = append(keys, added...)
+	}
+	obj.keys = keys
+}
+
+// Object converts the object to an unordered Object;
+// nested ordered objects are converted too.
+func (obj *OrderedObject) Object() Object {
+	if obj == nil {
+		return nil
+	}
+	res := make(Object, len(obj.keys))
+	for k, v := range obj.vals {
+		res[k] = fromOrdered(v)
+	}
+	return res
+}
+
+func fromOrdered(v any) any {
+	switch val := v.(type) {
+	case *OrderedObject:
+		return map[string]any(val.Object())
+	case []any:
+		return []any(fromOrderedArray(val))
+	case Array:
+		return fromOrderedArray(val)
+	}
+	return v
+}
+
+func fromOrderedArray(arr []any) Array {
+	res := make(Array, len(arr))
+	for i, v := range arr {
+		res[i] = fromOrdered(v)
+	}
+	return res
+}
+
+// Clone creates a deep clone of the object.
+func (obj *OrderedObject) Clone() *OrderedObject {
+	if obj == nil {
+		return nil
+	}
+	res := &OrderedObject{slices.Clone(obj.keys), make(map[string]any, len(obj.vals))}
+	for k, v := range obj.vals {
+		res.vals[k] = deepClone(v)
+	}
+	return res
+}
+
+// String converts the object to a JSON string.
+func (obj *OrderedObject) String() string {
+	return string(obj.Bytes())
+}
+
+// Bytes converts the object to bytes (JSON).
+func (obj *OrderedObject) Bytes() []byte {
+	return must(obj.MarshalJSON())
+}
+
+// MarshalJSON encodes the object with keys in insertion order.
+func (obj *OrderedObject) MarshalJSON() ([]byte, error) {
+	if obj == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range obj.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(obj.vals[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object keeping the key order;
+// nested objects are decoded as *OrderedObject.
+func (obj *OrderedObject) UnmarshalJSON(data []byte) error {
+	v, err := decodeOrdered(data, false)
+	if err != nil {
+		return toSyntaxError(data, err)
+	}
+	res, ok := v.(*OrderedObject)
+	if !ok {
+		return fmt.Errorf("js: cannot unmarshal %s into OrderedObject", typeName(v))
+	}
+	*obj = *res
+	return nil
+}
+
+// OrderedObject returns the value as an ordered object: as is if it was
+// parsed with ParseOptions.Ordered, converted with sorted keys otherwise.
+func (v Value) OrderedObject() *OrderedObject {
+	if obj, ok := v.val.(*OrderedObject); ok {
+		return obj
+	}
+	return ToOrderedObject(v.Object())
+}
+
+// decodeOrdered decodes a JSON document with objects as *OrderedObject.
+func decodeOrdered(data []byte, useNumber bool) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if useNumber {
+		dec.UseNumber()
+	}
+	v, err := decodeOrderedValue(dec)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEOF(data, dec); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func decodeOrderedValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &OrderedObject{vals: map[string]any{}}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.Set(key.(string), v)
+		}
+		_, err = dec.Token() // }
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			v, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token() // ]
+		return arr, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,97 @@
+package js
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOrderedObject(t *testing.T) {
+	obj := NewOrderedObject("z", 1, "a", 2)
+	obj.Set("m", Object{"y": 1, "b": 2}).Set("z", 3)
+
+	require(t, strings.Join(obj.Keys(), ",") == "z,a,m")
+	require(t, obj.GetInt("z") == 3 && obj.Len() == 3)
+	require(t, obj.String() == `{"z":3,"a":2,"m":{"b":2,"y":1}}`)
+
+	obj.Delete("a").Delete("nope")
+	require(t, strings.Join(obj.Keys(), ",") == "z,m")
+	require(t, obj.GetObj("m").GetInt("y") == 1)
+
+	var keys []string
+	for k := range obj.All() {
+		keys = append(keys, k)
+	}
+	require(t, strings.Join(keys, ",") == "z,m")
+
+	var zero OrderedObject
+	zero.Set("b", 1).Set("a", 2)
+	require(t, zero.String() == `{"b":1,"a":2}`)
+}
+
+func TestParseOrderedObject(t *testing.T) {
+	src := `{"sig":"x","payload":{"z":1,"a":[{"k2":1,"k1":2}]},"id":3}`
+	obj, err := ParseOrderedObject([]byte(src))
+
+	require(t, err == nil && obj.String() == src)
+	require(t, obj.GetObj("payload").GetArr("a").Eq(0).OrderedObject().Keys()[0] == "k2")
+	require(t, obj.Object().GetObj("payload").GetInt("z") == 1)
+	require(t, ToOrderedObject(obj.Object()).String() == `{"id":3,"payload":{"a":[{"k1":2,"k2":1}],"z":1},"sig":"x"}`)
+
+	clone := obj.Clone()
+	clone.GetObj("payload").Set("z", 5)
+	require(t, obj.GetObj("payload").GetInt("z") == 1)
+
+	_, err = ParseOrderedObject([]byte(`[1]`))
+	require(t, err != nil)
+	_, err = ParseOrderedObject([]byte(`{"a":1`))
+	require(t, err != nil)
+	_, err = ParseOrderedObject([]byte(`{"a":1}}`))
+	require(t, err != nil)
+}
+
+func TestParse_Ordered(t *testing.T) {
+	src := `[{"b":1,"a":{"d":12345678901234567890,"c":null}}]`
+	v, err := Parse([]byte(src), ParseOptions{Ordered: true, UseNumber: true})
+
+	require(t, err == nil && v.JSON() == src)
+	require(t, v.IsArray() && v.Path("[0]").IsObject())
+	require(t, v.Path("[0].a.d").String() == "12345678901234567890")
+	require(t, v.Path("[0].b").Int() == 1)
+	require(t, v.Array().Eq(0).OrderedObject().Keys()[0] == "b")
+	require(t, deepEqual(v.val, MustParse([]byte(src), ParseOptions{UseNumber: true}).val))
+}
+
+func TestOrderedObject_Mutations(t *testing.T) {
+	doc := func() Object {
+		cfg := must(ParseOrderedObject([]byte(`{"z":1,"m":{"y":1,"b":2},"a":[{"q":1,"p":2}]}`)))
+		return Object{"cfg": cfg}
+	}
+	cfgJSON := func(obj Object) string { return obj.Get("cfg").JSON() }
+
+	obj := doc()
+	require(t, obj.SetPath("cfg.m.c", 3) == nil && obj.SetPath("cfg.z", 0) == nil)
+	require(t, obj.SetPath("cfg.a[0].o", 3) == nil && obj.DeletePath("cfg.m.y") == nil)
+	require(t, cfgJSON(obj) == `{"z":0,"m":{"b":2,"c":3},"a":[{"q":1,"p":2,"o":3}]}`)
+
+	obj = doc()
+	require(t, obj.SetPointer("/cfg/m/0", true) == nil && obj.RemovePointer("/cfg/z") == nil)
+	require(t, cfgJSON(obj) == `{"m":{"y":1,"b":2,"0":true},"a":[{"q":1,"p":2}]}`)
+
+	v, err := ApplyPatch(NewValue(doc()), Array{
+		Object{"op": "add", "path": "/cfg/m/x", "value": 1},
+		Object{"op": "move", "from": "/cfg/z", "path": "/cfg/w"},
+	})
+	require(t, err == nil && v.Object().Get("cfg").JSON() == `{"m":{"y":1,"b":2,"x":1},"a":[{"q":1,"p":2}],"w":1}`)
+
+	obj = doc().MergePatch(Object{"cfg": Object{"m": Object{"y": nil, "e": 5}, "n": 1}})
+	require(t, cfgJSON(obj) == `{"z":1,"m":{"b":2,"e":5},"a":[{"q":1,"p":2}],"n":1}`)
+	obj = doc().DeepMerge(Object{"cfg": Object{"m": Object{"y": 0, "c": 1}}})
+	require(t, cfgJSON(obj) == `{"z":1,"m":{"y":0,"b":2,"c":1},"a":[{"q":1,"p":2}]}`)
+
+	// lookups do not convert the ordered objects
+	ordered := doc()["cfg"].(*OrderedObject)
+	m, _ := asObject(ordered)
+	require(t, reflect.ValueOf(m).Pointer() == reflect.ValueOf(ordered.vals).Pointer())
+	require(t, doc().GetPath("cfg.a[0].p").Int() == 2)
+}
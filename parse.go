@@ -0,0 +1,314 @@
+package js
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ParseOptions configures Parse, ParseObject, ReadValue, ReadObject and ParseFile.
+// Trailing data after the document is always rejected.
+type ParseOptions struct {
+	// Dialect selects the input syntax: DialectJSON (default), DialectJSONC
+	// (comments and trailing commas) or DialectJSON5.
+	Dialect Dialect
+
+	// UseNumber keeps numbers as json.Number literals instead of float64,
+	// so that integers above 2^53 (64-bit IDs) and decimal amounts
+	// are not rounded. Value.Int64, Uint64, BigInt, BigRat etc. read them exactly.
+	UseNumber bool
+
+	// Ordered decodes objects as *OrderedObject, keeping the key order
+	// of the document. It applies to Parse, ReadValue and ParseFile.
+	Ordered bool
+
+	// DisallowDuplicateKeys rejects objects with the same key more than once
+	// (by default the last one wins).
+	DisallowDuplicateKeys bool
+
+	// DisallowInvalidUTF8 rejects invalid UTF-8 (by default it is replaced with U+FFFD).
+	DisallowInvalidUTF8 bool
+
+	// DisallowNaN rejects string values that parse as NaN or infinity,
+	// like "NaN", "Infinity" or "-inf".
+	DisallowNaN bool
+
+	// MaxDepth limits the nesting of objects and arrays
+	// (0 means the default limit of 10000, as in encoding/json).
+	MaxDepth int
+
+	// MaxSize limits the size of the document in bytes (0 means no limit).
+	MaxSize int
+}
+
+func parseOptions(opts []ParseOptions) (o ParseOptions) {
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	return
+}
+
+func (o ParseOptions) strict() bool {
+	return o.DisallowDuplicateKeys || o.DisallowInvalidUTF8 || o.DisallowNaN || o.MaxDepth > 0 || o.MaxSize > 0
+}
+
+// SyntaxError describes JSON input that is malformed or rejected by ParseOptions.
+// It is returned by all parse functions (Parse, ParseObject, ReadValue,
+// ParseFile, UnmarshalFile, Load, etc.).
+type SyntaxError struct {
+	Msg     string
+	File    string // file name or URL, if known
+	Offset  int64  // byte offset of the error in the input
+	Line    int    // 1-based line; 0 if unknown (see StreamArray)
+	Column  int    // 1-based column (in bytes)
+	Excerpt string // the line of the input with a caret under the error position
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 { // position in a stream is known by offset only
+		return fmt.Sprintf("js: %s at offset %d", e.Msg, e.Offset)
+	}
+	if e.File != "" {
+		return fmt.Sprintf("js: %s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("js: %s at line %d, column %d", e.Msg, e.Line, e.Column)
+}
+
+func newSyntaxError(data []byte, offset int64, format string, args ...any) *SyntaxError {
+	offset = min(max(offset, 0), int64(len(data)))
+	line, lineStart := 1, 0
+	for i, c := range data[:offset] {
+		if c == '\n' {
+			line, lineStart = line+1, i+1
+		}
+	}
+	col := int(offset) - lineStart + 1
+	return &SyntaxError{
+		Msg:     fmt.Sprintf(format, args...),
+		Offset:  offset,
+		Line:    line,
+		Column:  col,
+		Excerpt: excerptLine(data[lineStart:], col-1),
+	}
+}
+
+// excerptLine returns the line starting at data, cut to at most 80 bytes
+// around pos, followed by a line with a caret at pos.
+func excerptLine(data []byte, pos int) string {
+	const width = 80
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		data = data[:end]
+	}
+	data = bytes.TrimRight(data, "\r")
+	from := 0
+	if pos > width/2 && len(data) > width {
+		from = min(pos-width/2, len(data)-width)
+	}
+	data = data[from:min(len(data), from+width)]
+	pos -= from
+	caret := make([]byte, 0, pos+1)
+	for i := 0; i < pos; i++ {
+		if i < len(data) && data[i] == '\t' {
+			caret = append(caret, '\t')
+		} else {
+			caret = append(caret, ' ')
+		}
+	}
+	return string(data) + "\n" + string(append(caret, '^'))
+}
+
+// toSyntaxError converts decoding errors of encoding/json to *SyntaxError.
+func toSyntaxError(data []byte, err error) error {
+	var se *json.SyntaxError
+	switch {
+	case errors.As(err, &se):
+		offset := se.Offset
+		if offset > 0 && offset <= int64(len(data)) && se.Error() != "unexpected end of JSON input" {
+			offset-- // the offset is after the offending byte
+		}
+		return newSyntaxError(data, offset, "%s", se.Error())
+	case err == io.ErrUnexpectedEOF:
+		return newSyntaxError(data, int64(len(data)), "unexpected end of JSON input")
+	}
+	return err
+}
+
+// withFile sets the file name of a *SyntaxError in err.
+func withFile(err error, file string) error {
+	var se *SyntaxError
+	if errors.As(err, &se) {
+		se.File = file
+	}
+	return err
+}
+
+// readInput reads the document from r, reading at most MaxSize+1 bytes if it is set.
+func readInput(r io.Reader, opts ParseOptions) []byte {
+	if opts.MaxSize <= 0 {
+		return readAll(r)
+	}
+	if c, ok := r.(io.ReadCloser); ok && c != nil {
+		defer c.Close()
+	}
+	return must(io.ReadAll(io.LimitReader(r, int64(opts.MaxSize)+1)))
+}
+
+// unmarshal decodes a single JSON document into v.
+func unmarshal(data []byte, v any, opts ParseOptions) error {
+	if opts.Dialect != DialectJSON {
+		// the other checks are done by the dialect parser
+		limits := ParseOptions{MaxSize: opts.MaxSize, DisallowInvalidUTF8: opts.DisallowInvalidUTF8}
+		if err := validate(data, limits); err != nil {
+			return err
+		}
+		return unmarshalDialect(data, v, opts)
+	}
+	if opts.strict() {
+		if err := validate(data, opts); err != nil {
+			return err
+		}
+	}
+	return toSyntaxError(data, decode(data, v, opts))
+}
+
+func decode(data []byte, v any, opts ParseOptions) error {
+	if p, ok := v.(*any); ok && opts.Ordered {
+		res, err := decodeOrdered(data, opts.UseNumber)
+		if err == nil {
+			*p = res
+		}
+		return err
+	}
+	switch p := v.(type) {
+	case *any:
+		res, err := decodeJSON(data, opts.UseNumber)
+		if err == nil {
+			*p = res
+		}
+		return err
+	case *Object:
+		res, err := decodeJSON(data, opts.UseNumber)
+		if err != nil {
+			return err
+		}
+		switch res := res.(type) {
+		case map[string]any:
+			*p = res
+		case nil:
+			*p = nil
+		default:
+			return &json.UnmarshalTypeError{Value: jsonKind(res).String(), Type: reflect.TypeFor[Object]()}
+		}
+		return nil
+	}
+	if !opts.UseNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return checkEOF(data, dec)
+}
+
+// checkEOF reports data remaining in dec after the top-level value.
+func checkEOF(data []byte, dec *json.Decoder) error {
+	offset := tokenStart(data, dec.InputOffset())
+	if _, err := dec.Token(); err != io.EOF {
+		return newSyntaxError(data, offset, "invalid character after top-level value")
+	}
+	return nil
+}
+
+// validate checks the strict options of opts.
+// Malformed JSON is left to be reported by the decoder.
+func validate(data []byte, opts ParseOptions) error {
+	if opts.MaxSize > 0 && len(data) > opts.MaxSize {
+		return newSyntaxError(data, int64(opts.MaxSize), "document exceeds %d bytes", opts.MaxSize)
+	}
+	if opts.DisallowInvalidUTF8 && !utf8.Valid(data) {
+		i := 0
+		for i < len(data) {
+			r, n := utf8.DecodeRune(data[i:])
+			if r == utf8.RuneError && n == 1 {
+				break
+			}
+			i += n
+		}
+		return newSyntaxError(data, int64(i), "invalid UTF-8")
+	}
+	if !opts.DisallowDuplicateKeys && !opts.DisallowNaN && opts.MaxDepth <= 0 {
+		return nil
+	}
+	type frame struct {
+		keys      map[string]bool // nil for arrays
+		expectKey bool
+	}
+	var stack []frame
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		start := tokenStart(data, dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if n := len(stack); n > 0 && stack[n-1].expectKey {
+			if key, ok := tok.(string); ok {
+				top := &stack[n-1]
+				if opts.DisallowDuplicateKeys && top.keys[key] {
+					return newSyntaxError(data, start, "duplicate key %s", strconv.Quote(key))
+				}
+				top.keys[key], top.expectKey = true, false
+				continue
+			}
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if opts.MaxDepth > 0 && len(stack) >= opts.MaxDepth {
+				return newSyntaxError(data, start, "exceeded max depth %d", opts.MaxDepth)
+			}
+			f := frame{}
+			if tok == json.Delim('{') {
+				f = frame{map[string]bool{}, true}
+			}
+			stack = append(stack, f)
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		default:
+			if s, ok := tok.(string); ok && opts.DisallowNaN {
+				if f, err := strconv.ParseFloat(s, 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+					return newSyntaxError(data, start, "NaN-like string %s", strconv.Quote(s))
+				}
+			}
+		}
+		// a value is complete
+		if len(stack) > 0 && stack[len(stack)-1].keys != nil {
+			stack[len(stack)-1].expectKey = true
+		}
+	}
+}
+
+// tokenStart returns the offset of the first token at or after offset,
+// skipping whitespace and separators.
+func tokenStart(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
@@ -0,0 +1,123 @@
+package js
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse_Strict(t *testing.T) {
+	parseErr := func(src string, opts ParseOptions) *SyntaxError {
+		_, err := Parse([]byte(src), opts)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			return nil
+		}
+		return se
+	}
+
+	dup := ParseOptions{DisallowDuplicateKeys: true}
+	se := parseErr("{\n  \"a\": 1,\n  \"b\": {\"a\": 2},\n  \"a\": 3\n}", dup)
+	require(t, se != nil && se.Line == 4 && se.Column == 3 && strings.Contains(se.Error(), `duplicate key "a"`))
+	require(t, parseErr(`{"a":{},"b":[{"a":1},{"a":2}],"c":{"x":1,"y":{}}}`, dup) == nil)
+	_, err := Parse([]byte(`{"a":1,"a":2}`))
+	require(t, err == nil)
+
+	depth := ParseOptions{MaxDepth: 2}
+	require(t, parseErr(`{"a":[1]}`, depth) == nil)
+	se = parseErr(`{"a":[{"b":1}]}`, depth)
+	require(t, se != nil && se.Offset == 6)
+
+	nan := ParseOptions{DisallowNaN: true}
+	require(t, parseErr(`{"NaN":"x","v":"1.5"}`, nan) == nil)
+	require(t, parseErr(`{"v":"NaN"}`, nan) != nil)
+	require(t, parseErr(`["-Infinity"]`, nan) != nil)
+
+	utf := ParseOptions{DisallowInvalidUTF8: true}
+	se = parseErr("{\"a\":\"x\xffy\"}", utf)
+	require(t, se != nil && se.Offset == 7 && se.Column == 8)
+	v, err := Parse([]byte("{\"a\":\"x\xffy\"}"))
+	require(t, err == nil && v.Path("a").String() == "x�y")
+
+	size := ParseOptions{MaxSize: 10}
+	require(t, parseErr(`{"a":"0123456789"}`, size) != nil)
+	require(t, parseErr(`{"a":1}`, size) == nil)
+	_, err = ReadValue(strings.NewReader(`{"a":"0123456789"}`), size)
+	require(t, err != nil)
+	filename := filepath.Join(t.TempDir(), "big.json")
+	require(t, os.WriteFile(filename, []byte(`["`+strings.Repeat("x", 1<<20)+`"]`), 0600) == nil)
+	_, err = ParseFile(filename, size)
+	require(t, err != nil && strings.Contains(err.Error(), "document exceeds 10 bytes"))
+	_, err = ParseFile(filename)
+	require(t, err == nil)
+
+	// malformed input is still reported
+	_, err = Parse([]byte(`{"a":1,}`), dup)
+	require(t, err != nil)
+}
+
+func TestParse_TrailingData(t *testing.T) {
+	_, err1 := Parse([]byte(`{"a":1} {"b":2}`))
+	_, err2 := ReadValue(strings.NewReader(`[1] 2`))
+	_, err3 := ParseObject([]byte(`{} x`), ParseOptions{UseNumber: true})
+
+	filename := filepath.Join(t.TempDir(), "a.json")
+	require(t, os.WriteFile(filename, []byte(`{"a":1}{}`), 0600) == nil)
+	_, err4 := ParseFile(filename)
+
+	require(t, err1 != nil && err2 != nil && err3 != nil && err4 != nil)
+}
+
+func TestSyntaxError(t *testing.T) {
+	src := "{\n\t\"a\": 1,\n\t\"b\": x\n}"
+	_, err := Parse([]byte(src))
+
+	var se *SyntaxError
+	require(t, errors.As(err, &se))
+	require(t, se.Line == 3 && se.Column == 7 && se.Offset == 17)
+	require(t, se.Excerpt == "\t\"b\": x\n\t     ^")
+	require(t, strings.HasPrefix(err.Error(), "js: invalid character 'x' looking for beginning of value at line 3, column 7"))
+
+	_, err = Parse([]byte(`{"a":[1,2`))
+	require(t, errors.As(err, &se) && se.Offset == 9 && se.Column == 10)
+	_, err = Parse([]byte(`{"a":1} x`), ParseOptions{UseNumber: true})
+	require(t, errors.As(err, &se) && se.Offset == 8)
+	_, err = Parse([]byte(`{"a":1} x`), ParseOptions{Ordered: true})
+	require(t, errors.As(err, &se) && se.Offset == 8)
+
+	long := `{"key":"` + strings.Repeat("x", 200) + `",}`
+	_, err = Parse([]byte(long))
+	require(t, errors.As(err, &se) && se.Column == len(long))
+	lines := strings.Split(se.Excerpt, "\n")
+	require(t, len(lines[0]) == 80 && len(lines[1]) == 80 && strings.HasSuffix(lines[0], `",}`))
+}
+
+func TestSyntaxError_File(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	require(t, os.WriteFile(filename, []byte("{\n  \"a\": [1 2]\n}"), 0600) == nil)
+
+	_, err1 := ParseFile(filename)
+	var m map[string]any
+	err2 := UnmarshalFile(filename, &m)
+
+	for _, err := range []error{err1, err2} {
+		var se *SyntaxError
+		require(t, errors.As(err, &se) && se.File == filename && se.Line == 2 && se.Column == 11)
+		require(t, strings.HasPrefix(err.Error(), "js: "+filename+":2:11: invalid character '2'"))
+	}
+}
+
+func TestSyntaxError_Load(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"price": 1.5,}`))
+	}))
+	defer srv.Close()
+
+	_, err := Load(srv.URL)
+	var se *SyntaxError
+	require(t, errors.As(err, &se) && se.File == srv.URL && se.Column == 15)
+}
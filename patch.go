@@ -0,0 +1,271 @@
+package js
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PatchOptions configures ApplyPatchWith.
+type PatchOptions struct {
+	// StrictTest makes the "test" operation require values of the same type.
+	// By default values are compared like Value.Equal does, so 1 equals "1".
+	StrictTest bool
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to the document and returns
+// the patched copy. The patch is atomic: on any failing operation the
+// error is returned and the original document is left untouched.
+func ApplyPatch(doc Value, patch Array) (Value, error) {
+	return ApplyPatchWith(doc, patch, PatchOptions{})
+}
+
+// ApplyPatchWith applies a JSON Patch (RFC 6902) with the given options.
+func ApplyPatchWith(doc Value, patch Array, opts PatchOptions) (Value, error) {
+	res := deepClone(doc.val)
+	for i, v := range patch {
+		op, _ := asObject(v)
+		var err error
+		if res, err = applyPatchOp(res, op, opts); err != nil {
+			return doc, fmt.Errorf("js: patch operation %d (%s): %w", i, op.GetStr("op"), err)
+		}
+	}
+	return Value{res}, nil
+}
+
+func applyPatchOp(doc any, op Object, opts PatchOptions) (any, error) {
+	pointer := func(name string) (Pointer, error) {
+		if !op.Has(name) {
+			return nil, fmt.Errorf("missing `%s`", name)
+		}
+		s, ok := op[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("`%s` must be a string", name)
+		}
+		return ParsePointer(s)
+	}
+	path, err := pointer("path")
+	if err != nil {
+		return nil, err
+	}
+	value := func() (any, error) {
+		if !op.Has("value") {
+			return nil, fmt.Errorf("missing `value`")
+		}
+		return deepClone(op["value"]), nil
+	}
+	switch op.GetStr("op") {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return path.set(doc, v, true)
+
+	case "remove":
+		return path.remove(doc)
+
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = path.Get(Value{doc}); err != nil {
+			return nil, err
+		}
+		return path.set(doc, v, false)
+
+	case "move":
+		from, err := pointer("from")
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(from) && path[:len(from)].String() == from.String() {
+			return nil, fmt.Errorf("can not move `%s` into its own child `%s`", from, path)
+		}
+		v, err := from.Get(Value{doc})
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = from.remove(doc); err != nil {
+			return nil, err
+		}
+		return path.set(doc, v.val, true)
+
+	case "copy":
+		from, err := pointer("from")
+		if err != nil {
+			return nil, err
+		}
+		v, err := from.Get(Value{doc})
+		if err != nil {
+			return nil, err
+		}
+		return path.set(doc, deepClone(v.val), true)
+
+	case "test":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		cur, err := path.Get(Value{doc})
+		if err != nil {
+			return nil, err
+		}
+		equal := cur.Equal(v)
+		if opts.StrictTest {
+			equal = deepEqual(cur.val, v)
+		}
+		if !equal {
+			return nil, fmt.Errorf("test failed: `%s` is %s, not %s", path, Encode(cur.val), Encode(v))
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation `%s`", op.GetStr("op"))
+}
+
+// Diff returns a JSON Patch (RFC 6902) that transforms a into b.
+// Objects are compared member by member and arrays element by element
+// (using their longest common subsequence), so that unchanged parts
+// produce no operations.
+func Diff(a, b Value) Array {
+	patch := Array{}
+	diffValues(&patch, Pointer{}, a.val, b.val)
+	return patch
+}
+
+func diffValues(patch *Array, path Pointer, a, b any) {
+	if deepEqual(a, b) {
+		return
+	}
+	if aa, ok := asArray(a); ok {
+		if bb, ok := asArray(b); ok {
+			diffArrays(patch, path, aa, bb)
+			return
+		}
+	} else if oa, ok := asObject(a); ok {
+		if ob, ok := asObject(b); ok {
+			for _, k := range oa.Keys() {
+				if _, ok := ob[k]; !ok {
+					patch.Push(Object{"op": "remove", "path": path.Append(k).String()})
+				}
+			}
+			for _, k := range ob.Keys() {
+				if v, ok := oa[k]; ok {
+					diffValues(patch, path.Append(k), v, ob[k])
+				} else {
+					patch.Push(Object{"op": "add", "path": path.Append(k).String(), "value": ob[k]})
+				}
+			}
+			return
+		}
+	}
+	patch.Push(Object{"op": "replace", "path": path.String(), "value": b})
+}
+
+func diffArrays(patch *Array, path Pointer, a, b Array) {
+	cur := 0 // index in the array being patched
+	for _, e := range alignArrays(a, b) {
+		at := path.Append(strconv.Itoa(cur))
+		switch {
+		case e.i < 0:
+			patch.Push(Object{"op": "add", "path": at.String(), "value": b[e.j]})
+			cur++
+		case e.j < 0:
+			patch.Push(Object{"op": "remove", "path": at.String()})
+		default:
+			diffValues(patch, at, a[e.i], b[e.j])
+			cur++
+		}
+	}
+}
+
+// arrayEdit is a step of an alignment of arrays a and b: a[i] corresponds
+// to b[j] (equal if same is set); i < 0 means b[j] is inserted,
+// j < 0 means a[i] is removed.
+type arrayEdit struct {
+	i, j int
+	same bool
+}
+
+// maxAlignCells limits the size of the LCS table of alignArrays;
+// larger arrays are aligned by position.
+const maxAlignCells = 1 << 22
+
+// alignArrays aligns two arrays by their longest common subsequence.
+// Within each run of differences, changed pairs come first,
+// then removals, then insertions.
+func alignArrays(a, b Array) (edits []arrayEdit) {
+	// skip the common prefix and suffix
+	pre := 0
+	for pre < len(a) && pre < len(b) && deepEqual(a[pre], b[pre]) {
+		edits = append(edits, arrayEdit{pre, pre, true})
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && deepEqual(a[len(a)-1-suf], b[len(b)-1-suf]) {
+		suf++
+	}
+	n, m := len(a)-pre-suf, len(b)-pre-suf
+	if (n+1)*(m+1) > maxAlignCells {
+		// too large for the table: pair the elements by position
+		for k := 0; k < n || k < m; k++ {
+			switch {
+			case k >= m:
+				edits = append(edits, arrayEdit{pre + k, -1, false})
+			case k >= n:
+				edits = append(edits, arrayEdit{-1, pre + k, false})
+			default:
+				edits = append(edits, arrayEdit{pre + k, pre + k, deepEqual(a[pre+k], b[pre+k])})
+			}
+		}
+		n, m = 0, 0 // nothing left for the table
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[pre+i:] and b[pre+j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if deepEqual(a[pre+i], b[pre+j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < n || j < m; {
+		if i < n && j < m && deepEqual(a[pre+i], b[pre+j]) {
+			edits = append(edits, arrayEdit{pre + i, pre + j, true})
+			i, j = i+1, j+1
+			continue
+		}
+		// collect a run of removals and insertions
+		var dels, ins []int
+		for i < n || j < m {
+			if i < n && j < m && deepEqual(a[pre+i], b[pre+j]) {
+				break
+			}
+			if j == m || i < n && lcs[i+1][j] >= lcs[i][j+1] {
+				dels, i = append(dels, pre+i), i+1
+			} else {
+				ins, j = append(ins, pre+j), j+1
+			}
+		}
+		k := 0
+		for ; k < len(dels) && k < len(ins); k++ {
+			edits = append(edits, arrayEdit{dels[k], ins[k], false})
+		}
+		for ; k < len(dels); k++ {
+			edits = append(edits, arrayEdit{dels[k], -1, false})
+		}
+		for ; k < len(ins); k++ {
+			edits = append(edits, arrayEdit{-1, ins[k], false})
+		}
+	}
+	for k := suf; k > 0; k-- {
+		edits = append(edits, arrayEdit{len(a) - k, len(b) - k, true})
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package js
+
+import "testing"
+
+func TestApplyPatch(t *testing.T) {
+	doc := MustParse([]byte(`{"a":{"b":[1,2,3]},"c":"x"}`))
+	patch := MustParse([]byte(`[
+		{"op":"test","path":"/c","value":"x"},
+		{"op":"add","path":"/a/b/1","value":9},
+		{"op":"remove","path":"/a/b/0"},
+		{"op":"replace","path":"/c","value":{"d":1}},
+		{"op":"move","from":"/c/d","path":"/a/e"},
+		{"op":"copy","from":"/a/b","path":"/f"},
+		{"op":"add","path":"/f/-","value":4}
+	]`)).Array()
+
+	res, err := ApplyPatch(doc, patch)
+
+	require(t, err == nil)
+	require(t, res.JSON() == `{"a":{"b":[9,2,3],"e":1},"c":{},"f":[9,2,3,4]}`)
+	require(t, doc.JSON() == `{"a":{"b":[1,2,3]},"c":"x"}`)
+}
+
+func TestApplyPatch_atomic(t *testing.T) {
+	doc := MustParse([]byte(`{"a":[1],"n":1}`))
+	patch := MustParse([]byte(`[
+		{"op":"add","path":"/a/-","value":2},
+		{"op":"remove","path":"/missing"}
+	]`)).Array()
+
+	res, err := ApplyPatch(doc, patch)
+
+	require(t, err != nil)
+	require(t, res.JSON() == `{"a":[1],"n":1}`)
+	require(t, doc.JSON() == `{"a":[1],"n":1}`)
+}
+
+func TestApplyPatch_test(t *testing.T) {
+	doc := MustParse([]byte(`{"n":1}`))
+	patch := MustParse([]byte(`[{"op":"test","path":"/n","value":"1"}]`)).Array()
+
+	_, err1 := ApplyPatch(doc, patch)
+	_, err2 := ApplyPatchWith(doc, patch, PatchOptions{StrictTest: true})
+
+	require(t, err1 == nil)
+	require(t, err2 != nil)
+}
+
+func TestApplyPatch_fail(t *testing.T) {
+	doc := MustParse([]byte(`{"a":{"b":1},"arr":[1]}`))
+
+	for _, p := range []string{
+		`[{"op":"nope","path":"/a"}]`,
+		`[{"op":"add","value":1}]`,
+		`[{"op":"add","path":"/x/y","value":1}]`,
+		`[{"op":"add","path":"/arr/5","value":1}]`,
+		`[{"op":"replace","path":"/x","value":1}]`,
+		`[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+		`[{"op":"test","path":"/a/b","value":2}]`,
+		`[{"op":"add","path":"/a"}]`,
+	} {
+		_, err := ApplyPatch(doc, MustParse([]byte(p)).Array())
+		require(t, err != nil)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, c := range [][2]string{
+		{`{"a":1,"b":[1,2,3],"c":{"d":1}}`, `{"a":2,"b":[1,3,4],"c":{"e":1},"f":null}`},
+		{`[1,2,3,4,5]`, `[0,1,3,5,6]`},
+		{`[{"id":1,"v":1},{"id":2}]`, `[{"id":1,"v":2},{"id":3},{"id":2}]`},
+		{`{"a":[1]}`, `[1]`},
+		{`[]`, `[1,[2]]`},
+		{`[1,2]`, `[]`},
+	} {
+		a, b := MustParse([]byte(c[0])), MustParse([]byte(c[1]))
+
+		patch := Diff(a, b)
+		res, err := ApplyPatch(a, patch)
+
+		require(t, err == nil && deepEqual(res.val, b.val))
+	}
+}
+
+func TestDiff_minimal(t *testing.T) {
+	a := MustParse([]byte(`{"list":[1,2,3,4],"x":{"y":1,"z":2}}`))
+	b := MustParse([]byte(`{"list":[1,2,9,3,4],"x":{"y":1,"z":3}}`))
+
+	patch := Diff(a, b)
+
+	require(t, patch.String() == `[{"op":"add","path":"/list/2","value":9},{"op":"replace","path":"/x/z","value":3}]`)
+	require(t, Diff(a, a).String() == `[]`)
+}
+
+func TestDiff_largeArrays(t *testing.T) {
+	a, b := make(Array, 20000), make(Array, 20010)
+	for i := range a {
+		a[i] = i
+	}
+	for i := range b {
+		b[i] = -i - 1
+	}
+	b[5] = 5
+
+	// too large for the LCS table: the elements are paired by position
+	patch := Diff(NewValue(a), NewValue(b))
+	require(t, patch.Len() == 20010-1 && patch.Eq(0).Object().GetStr("path") == "/0" && patch.Eq(-1).Object().GetStr("op") == "add")
+	v, err := ApplyPatch(NewValue(a), patch)
+	require(t, err == nil && v.JSON() == b.String())
+	require(t, len(DiffValues(NewValue(a), NewValue(b))) == 20010-1)
+}
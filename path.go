@@ -0,0 +1,452 @@
+package js
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrNotObject = errors.New("not an object")
+	ErrNotArray  = errors.New("not an array")
+)
+
+// PathError describes a path lookup that failed at a particular segment.
+type PathError struct {
+	Path    string // full path
+	Segment string // path prefix up to and including the failed segment
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("js: path `%s`: `%s` %v", e.Path, e.Segment, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// pathSegment is a single step of a path: an object key (`.key`, `["key"]`)
+// or an array index (`[3]`, `[-1]`).
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (s pathSegment) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	if s.key == "" || strings.ContainsAny(s.key, ".[]\"") {
+		return "[" + strconv.Quote(s.key) + "]"
+	}
+	return s.key
+}
+
+func formatPath(segs []pathSegment) string {
+	var sb strings.Builder
+	for i, s := range segs {
+		if str := s.String(); str[0] == '[' || i == 0 {
+			sb.WriteString(str)
+		} else {
+			sb.WriteString("." + str)
+		}
+	}
+	return sb.String()
+}
+
+// parsePath parses paths like `data.items[3].price` or `["a.b"][0]`.
+func parsePath(path string) (segs []pathSegment, err error) {
+	fail := func(msg string) ([]pathSegment, error) {
+		return nil, fmt.Errorf("js: invalid path `%s`: %s", path, msg)
+	}
+	for i := 0; i < len(path); {
+		switch c := path[i]; {
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return fail("unclosed `[`")
+			}
+			inner := path[i+1 : i+end]
+			if len(inner) > 0 && (inner[0] == '"' || inner[0] == '\'') {
+				// quoted key; the closing bracket may be inside the quotes
+				q := inner[0]
+				j := i + 2
+				for ; j < len(path) && path[j] != q; j++ {
+					if path[j] == '\\' {
+						j++
+					}
+				}
+				if j+1 >= len(path) || path[j+1] != ']' {
+					return fail("unterminated quoted key")
+				}
+				key := path[i+2 : j]
+				if q == '"' {
+					if key, err = strconv.Unquote(`"` + key + `"`); err != nil {
+						return fail("bad quoted key")
+					}
+				} else {
+					key = strings.ReplaceAll(key, `\'`, `'`)
+				}
+				segs = append(segs, pathSegment{key: key})
+				i = j + 2
+			} else {
+				n, err := strconv.Atoi(strings.TrimSpace(inner))
+				if err != nil {
+					return fail("bad index `" + inner + "`")
+				}
+				segs = append(segs, pathSegment{index: n, isIndex: true})
+				i += end + 1
+			}
+		case c == '.' && i > 0:
+			i++
+			fallthrough
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return fail("empty key at offset " + strconv.Itoa(i))
+			}
+			segs = append(segs, pathSegment{key: path[i : i+end]})
+			i += end
+		}
+	}
+	return
+}
+
+func lookupPath(v any, path string) (any, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range segs {
+		if v, err = lookupSegment(v, s); err != nil {
+			return nil, &PathError{path, formatPath(segs[:i+1]), err}
+		}
+	}
+	return v, nil
+}
+
+func lookupSegment(v any, s pathSegment) (any, error) {
+	if arr, ok := asArray(v); ok {
+		i, err := segmentIndex(s)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			i += len(arr)
+		}
+		if i < 0 || i >= len(arr) {
+			return nil, ErrNotFound
+		}
+		return arr[i], nil
+	}
+	if s.isIndex {
+		return nil, ErrNotArray
+	}
+	obj, ok := asObject(v)
+	if !ok {
+		return nil, ErrNotObject
+	}
+	val, ok := obj[s.key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val, nil
+}
+
+// asObject returns v as an Object without conversion of arbitrary Go types,
+// falling back to newObject for structs and foreign maps.
+// For an *OrderedObject it returns the underlying map: after changing it,
+// objectNode must be called to update the key order.
+func asObject(v any) (Object, bool) {
+	switch val := v.(type) {
+	case nil:
+		return nil, false
+	case map[string]any:
+		return val, true
+	case Object:
+		return val, true
+	case Value:
+		return asObject(val.val)
+	case *OrderedObject:
+		if val == nil {
+			return nil, false
+		}
+		if val.vals == nil {
+			val.vals = map[string]any{}
+		}
+		return val.vals, true
+	case string, bool, float64, []any, Array:
+		return nil, false
+	}
+	obj := newObject(v)
+	return obj, obj != nil
+}
+
+// objectNode returns the object node cur after its map obj (see asObject)
+// was changed: an *OrderedObject is kept, with its key order updated.
+func objectNode(cur any, obj Object) any {
+	if val, ok := cur.(Value); ok {
+		cur = val.val
+	}
+	if o, ok := cur.(*OrderedObject); ok && o != nil {
+		o.sync()
+		return o
+	}
+	return obj
+}
+
+func asArray(v any) (Array, bool) {
+	switch val := v.(type) {
+	case []any:
+		return val, true
+	case Array:
+		return val, true
+	case Value:
+		return asArray(val.val)
+	}
+	if val := NewValue(v); val.IsArray() {
+		return val.Array(), true
+	}
+	return nil, false
+}
+
+// Path retrieves a nested value by a path like `data.items[3].price`.
+// Negative indexes count from the end of an array, as in Array.Eq.
+// Returns an empty Value if any segment of the path is missing.
+func (v Value) Path(path string) Value {
+	val, _ := lookupPath(v.val, path)
+	return Value{val}
+}
+
+// LookupPath retrieves a nested value by path.
+// The returned *PathError reports the segment that could not be resolved.
+func (v Value) LookupPath(path string) (Value, error) {
+	val, err := lookupPath(v.val, path)
+	return Value{val}, err
+}
+
+// GetPath retrieves the nested value by path (see Value.Path).
+func (arr Array) GetPath(path string) Value {
+	return Value{arr}.Path(path)
+}
+
+// LookupPath retrieves the nested value by path, reporting the missing segment.
+func (arr Array) LookupPath(path string) (Value, error) {
+	return Value{arr}.LookupPath(path)
+}
+
+// GetPath retrieves the nested value by path (see Value.Path).
+func (obj Object) GetPath(path string) Value {
+	return Value{obj}.Path(path)
+}
+
+// LookupPath retrieves the nested value by path, reporting the missing segment.
+func (obj Object) LookupPath(path string) (Value, error) {
+	return Value{obj}.LookupPath(path)
+}
+
+// GetBoolPath retrieves the boolean value by path.
+func (obj Object) GetBoolPath(path string) bool {
+	return obj.GetPath(path).Bool()
+}
+
+// GetStrPath retrieves the string value by path.
+func (obj Object) GetStrPath(path string) string {
+	return obj.GetPath(path).String()
+}
+
+// GetNumPath retrieves the numeric value (float64) by path.
+func (obj Object) GetNumPath(path string) float64 {
+	return obj.GetPath(path).Float64()
+}
+
+// GetIntPath retrieves the integer value (int) by path.
+func (obj Object) GetIntPath(path string) int {
+	return obj.GetPath(path).Int()
+}
+
+// GetInt64Path retrieves the 64-bit integer value (int64) by path.
+func (obj Object) GetInt64Path(path string) int64 {
+	return obj.GetPath(path).Int64()
+}
+
+// GetUint64Path retrieves the unsigned 64-bit integer value (uint64) by path.
+func (obj Object) GetUint64Path(path string) uint64 {
+	return obj.GetPath(path).Uint64()
+}
+
+// GetTimePath retrieves the time value (time.Time) by path.
+func (obj Object) GetTimePath(path string) time.Time {
+	return obj.GetPath(path).Time()
+}
+
+// GetObjPath retrieves the object by path.
+func (obj Object) GetObjPath(path string) Object {
+	return obj.GetPath(path).Object()
+}
+
+// GetArrPath retrieves the array by path.
+func (obj Object) GetArrPath(path string) Array {
+	return obj.GetPath(path).Array()
+}
+
+// SetPath sets the value at path, creating missing intermediate objects and
+// arrays along the way (`a.b[2].c` creates an array for `b`, padded with null).
+// A segment that meets an existing value of the wrong type is reported
+// as a *PathError; the object is left unchanged in that case.
+func (obj Object) SetPath(path string, v any) error {
+	if obj == nil {
+		return &PathError{path, "", ErrNotObject}
+	}
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segs) == 0 {
+		return fmt.Errorf("js: SetPath: empty path")
+	}
+	_, err = setPath(obj, segs, 0, v, path)
+	return err
+}
+
+// DeletePath removes the value at path. Removing an array element shifts
+// the following elements. Returns a *PathError if the path does not exist.
+func (obj Object) DeletePath(path string) error {
+	if obj == nil {
+		return &PathError{path, "", ErrNotFound}
+	}
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segs) == 0 {
+		return fmt.Errorf("js: DeletePath: empty path")
+	}
+	_, err = deletePath(obj, segs, 0, path)
+	return err
+}
+
+// setPath sets v at segs[i:] inside cur and returns the (possibly new) container.
+func setPath(cur any, segs []pathSegment, i int, v any, path string) (any, error) {
+	if i == len(segs) {
+		return v, nil
+	}
+	s := segs[i]
+	fail := func(err error) (any, error) {
+		return nil, &PathError{path, formatPath(segs[:i+1]), err}
+	}
+	if isNil(cur) {
+		if s.isIndex {
+			cur = Array{}
+		} else {
+			cur = Object{}
+		}
+	}
+	if arr, ok := asArray(cur); ok {
+		idx, err := segmentIndex(s)
+		if err != nil {
+			return fail(err)
+		}
+		if idx < 0 {
+			if idx += len(arr); idx < 0 {
+				return fail(ErrNotFound)
+			}
+		}
+		var child any
+		if idx < len(arr) {
+			child = arr[idx]
+		}
+		child, err = setPath(child, segs, i+1, v, path)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= len(arr) {
+			arr = append(arr, make(Array, idx-len(arr)+1)...)
+		}
+		arr[idx] = child
+		return arr, nil
+	}
+	if s.isIndex {
+		return fail(ErrNotArray)
+	}
+	obj, ok := asObject(cur)
+	if !ok {
+		return fail(ErrNotObject)
+	}
+	child, err := setPath(obj[s.key], segs, i+1, v, path)
+	if err != nil {
+		return nil, err
+	}
+	obj[s.key] = child
+	return objectNode(cur, obj), nil
+}
+
+func deletePath(cur any, segs []pathSegment, i int, path string) (any, error) {
+	s, last := segs[i], i == len(segs)-1
+	fail := func(err error) (any, error) {
+		return nil, &PathError{path, formatPath(segs[:i+1]), err}
+	}
+	if arr, ok := asArray(cur); ok {
+		idx, err := segmentIndex(s)
+		if err != nil {
+			return fail(err)
+		}
+		if idx < 0 {
+			idx += len(arr)
+		}
+		if idx < 0 || idx >= len(arr) {
+			return fail(ErrNotFound)
+		}
+		if last {
+			return append(arr[:idx:idx], arr[idx+1:]...), nil
+		}
+		child, err := deletePath(arr[idx], segs, i+1, path)
+		if err != nil {
+			return nil, err
+		}
+		arr[idx] = child
+		return arr, nil
+	}
+	if s.isIndex {
+		return fail(ErrNotArray)
+	}
+	obj, ok := asObject(cur)
+	if !ok {
+		return fail(ErrNotObject)
+	}
+	child, ok := obj[s.key]
+	if !ok {
+		return fail(ErrNotFound)
+	}
+	if last {
+		delete(obj, s.key)
+		return objectNode(cur, obj), nil
+	}
+	child, err := deletePath(child, segs, i+1, path)
+	if err != nil {
+		return nil, err
+	}
+	obj[s.key] = child
+	return objectNode(cur, obj), nil
+}
+
+// segmentIndex returns the array index of s; dotted keys like `items.0` are
+// accepted as indexes too.
+func segmentIndex(s pathSegment) (int, error) {
+	if s.isIndex {
+		return s.index, nil
+	}
+	n, err := strconv.Atoi(s.key)
+	if err != nil {
+		return 0, ErrNotObject
+	}
+	return n, nil
+}
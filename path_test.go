@@ -0,0 +1,79 @@
+package js
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValue_Path(t *testing.T) {
+	v := MustParse([]byte(`{"data":{"items":[{"price":1.5},{"price":2},{"price":3,"tags":["a","b"]}]},"a.b":{"c":7}}`))
+
+	require(t, v.Path("data.items[1].price").Float64() == 2)
+	require(t, v.Path("data.items[-1].tags[-2]").String() == "a")
+	require(t, v.Path("data.items.0.price").Float64() == 1.5)
+	require(t, v.Path(`["a.b"].c`).Int() == 7)
+	require(t, v.Path("").Object() != nil)
+	require(t, v.Path("data.items[5].price").IsNull())
+	require(t, v.Path("data.nope.price").IsNull())
+}
+
+func TestValue_LookupPath(t *testing.T) {
+	v := MustParse([]byte(`{"data":{"items":[{"price":1}]}}`))
+
+	_, err1 := v.LookupPath("data.items[3].price")
+	_, err2 := v.LookupPath("data.items[0].price.x")
+	_, err3 := v.LookupPath("data[0]")
+	_, err4 := v.LookupPath("data.items[x]")
+
+	var pe *PathError
+	require(t, errors.As(err1, &pe) && pe.Segment == "data.items[3]" && errors.Is(err1, ErrNotFound))
+	require(t, errors.As(err2, &pe) && pe.Segment == "data.items[0].price.x" && errors.Is(err2, ErrNotObject))
+	require(t, errors.Is(err3, ErrNotArray))
+	require(t, err4 != nil && !errors.As(err4, &pe))
+}
+
+func TestObject_GetPath(t *testing.T) {
+	obj := MustParseObject([]byte(`{"a":{"b":[{"s":"x","n":"12","t":"2024-01-02"}]}}`))
+
+	require(t, obj.GetStrPath("a.b[0].s") == "x")
+	require(t, obj.GetIntPath("a.b[0].n") == 12)
+	require(t, obj.GetNumPath("a.b[-1].n") == 12)
+	require(t, obj.GetTimePath("a.b[0].t").Year() == 2024)
+	require(t, obj.GetObjPath("a.b[0]").GetStr("s") == "x")
+	require(t, obj.GetArrPath("a.b").Len() == 1)
+	require(t, NewArray(obj).GetPath("[0].a.b[0].s").String() == "x")
+}
+
+func TestObject_SetPath(t *testing.T) {
+	obj := Object{"x": 1, "arr": []any{1}}
+
+	err1 := obj.SetPath("a.b[2].c", "v")
+	err2 := obj.SetPath("arr[-1]", 10)
+	err3 := obj.SetPath("arr[3]", 13)
+	err4 := obj.SetPath(`a["k.1"]`, true)
+	err5 := obj.SetPath("x.y", 2)
+	err6 := obj.SetPath("a[0]", 2)
+	err7 := Object(nil).SetPath("a", 1)
+
+	var pe *PathError
+	require(t, err1 == nil && err2 == nil && err3 == nil && err4 == nil)
+	require(t, obj.String() == `{"a":{"b":[null,null,{"c":"v"}],"k.1":true},"arr":[10,null,null,13],"x":1}`)
+	require(t, errors.As(err5, &pe) && pe.Segment == "x.y" && errors.Is(err5, ErrNotObject))
+	require(t, errors.Is(err6, ErrNotArray))
+	require(t, err7 != nil)
+}
+
+func TestObject_DeletePath(t *testing.T) {
+	obj := MustParseObject([]byte(`{"a":{"b":[1,2,3],"c":{"d":1,"e":2}}}`))
+
+	err1 := obj.DeletePath("a.b[1]")
+	err2 := obj.DeletePath("a.c.d")
+	err3 := obj.DeletePath("a.b[-1]")
+	err4 := obj.DeletePath("a.c.zz")
+	err5 := obj.DeletePath("a.b.x")
+
+	require(t, err1 == nil && err2 == nil && err3 == nil)
+	require(t, obj.String() == `{"a":{"b":[1],"c":{"e":2}}}`)
+	require(t, errors.Is(err4, ErrNotFound))
+	require(t, errors.Is(err5, ErrNotObject))
+}
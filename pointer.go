@@ -0,0 +1,246 @@
+package js
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pointer is a parsed JSON Pointer (RFC 6901): a list of reference tokens.
+type Pointer []string
+
+var errPointerIndex = errors.New("invalid array index")
+
+// ParsePointer parses a JSON Pointer like `/a/b~1c/0`.
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("js: invalid JSON pointer `%s`: must start with `/`", s)
+	}
+	p := Pointer(strings.Split(s[1:], "/"))
+	for i, tok := range p {
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 == len(tok) || tok[j+1] != '0' && tok[j+1] != '1') {
+				return nil, fmt.Errorf("js: invalid JSON pointer `%s`: bad escape in `%s`", s, tok)
+			}
+		}
+		p[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return p, nil
+}
+
+// MustParsePointer parses a JSON Pointer and panics on error.
+func MustParsePointer(s string) Pointer {
+	return must(ParsePointer(s))
+}
+
+// String formats the pointer, escaping `~` and `/` in tokens.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, tok := range p {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// Append returns a new pointer with the given tokens added.
+func (p Pointer) Append(tokens ...string) Pointer {
+	return append(p[:len(p):len(p)], tokens...)
+}
+
+func (p Pointer) fail(i int, err error) error {
+	return &PathError{p.String(), p[:i+1].String(), err}
+}
+
+// Get resolves the pointer against v.
+func (p Pointer) Get(v Value) (Value, error) {
+	cur := v.val
+	for i, tok := range p {
+		if arr, ok := asArray(cur); ok {
+			idx, err := pointerIndex(tok, len(arr))
+			if err == nil && idx >= len(arr) {
+				err = ErrNotFound
+			}
+			if err != nil {
+				return Value{}, p.fail(i, err)
+			}
+			cur = arr[idx]
+			continue
+		}
+		obj, ok := asObject(cur)
+		if !ok {
+			return Value{}, p.fail(i, ErrNotObject)
+		}
+		if cur, ok = obj[tok]; !ok {
+			return Value{}, p.fail(i, ErrNotFound)
+		}
+	}
+	return Value{cur}, nil
+}
+
+// pointerIndex parses an array reference token; `-` refers to the
+// (nonexistent) element after the last one.
+func pointerIndex(tok string, n int) (int, error) {
+	if tok == "-" {
+		return n, nil
+	}
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') || tok[0] == '+' || tok[0] == '-' {
+		return 0, errPointerIndex
+	}
+	i, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, errPointerIndex
+	}
+	return i, nil
+}
+
+// set stores v at the pointer inside root and returns the (possibly new) root.
+// The parent of the target must exist. For arrays, insert shifts the
+// elements at and after the index (as JSON Patch "add" does); otherwise
+// the element is replaced. The `-` token always appends.
+func (p Pointer) set(root any, v any, insert bool) (any, error) {
+	if len(p) == 0 {
+		return v, nil
+	}
+	return p.setAt(root, 0, v, insert)
+}
+
+func (p Pointer) setAt(cur any, i int, v any, insert bool) (any, error) {
+	tok, last := p[i], i == len(p)-1
+	if arr, ok := asArray(cur); ok {
+		idx, err := pointerIndex(tok, len(arr))
+		if err == nil && (idx > len(arr) || idx == len(arr) && !(last && (insert || tok == "-"))) {
+			err = ErrNotFound
+		}
+		if err != nil {
+			return nil, p.fail(i, err)
+		}
+		if !last {
+			child, err := p.setAt(arr[idx], i+1, v, insert)
+			if err != nil {
+				return nil, err
+			}
+			arr[idx] = child
+			return arr, nil
+		}
+		if idx == len(arr) {
+			return append(arr, v), nil
+		}
+		if insert {
+			return append(arr[:idx:idx], append(Array{v}, arr[idx:]...)...), nil
+		}
+		arr[idx] = v
+		return arr, nil
+	}
+	obj, ok := asObject(cur)
+	if !ok {
+		return nil, p.fail(i, ErrNotObject)
+	}
+	if last {
+		obj[tok] = v
+		return objectNode(cur, obj), nil
+	}
+	child, ok := obj[tok]
+	if !ok {
+		return nil, p.fail(i, ErrNotFound)
+	}
+	child, err := p.setAt(child, i+1, v, insert)
+	if err != nil {
+		return nil, err
+	}
+	obj[tok] = child
+	return objectNode(cur, obj), nil
+}
+
+// remove deletes the target of the pointer inside root and returns
+// the (possibly new) root.
+func (p Pointer) remove(root any) (any, error) {
+	if len(p) == 0 {
+		return nil, fmt.Errorf("js: can not remove the document root")
+	}
+	return p.removeAt(root, 0)
+}
+
+func (p Pointer) removeAt(cur any, i int) (any, error) {
+	tok, last := p[i], i == len(p)-1
+	if arr, ok := asArray(cur); ok {
+		idx, err := pointerIndex(tok, len(arr))
+		if err == nil && idx >= len(arr) {
+			err = ErrNotFound
+		}
+		if err != nil {
+			return nil, p.fail(i, err)
+		}
+		if last {
+			return append(arr[:idx:idx], arr[idx+1:]...), nil
+		}
+		child, err := p.removeAt(arr[idx], i+1)
+		if err != nil {
+			return nil, err
+		}
+		arr[idx] = child
+		return arr, nil
+	}
+	obj, ok := asObject(cur)
+	if !ok {
+		return nil, p.fail(i, ErrNotObject)
+	}
+	child, ok := obj[tok]
+	if !ok {
+		return nil, p.fail(i, ErrNotFound)
+	}
+	if last {
+		delete(obj, tok)
+		return objectNode(cur, obj), nil
+	}
+	child, err := p.removeAt(child, i+1)
+	if err != nil {
+		return nil, err
+	}
+	obj[tok] = child
+	return objectNode(cur, obj), nil
+}
+
+// Pointer retrieves a nested value by a JSON Pointer like `/a/b/0`.
+// The returned *PathError reports the reference token that failed to resolve.
+func (v Value) Pointer(ptr string) (Value, error) {
+	p, err := ParsePointer(ptr)
+	if err != nil {
+		return Value{}, err
+	}
+	return p.Get(v)
+}
+
+// SetPointer sets the value referenced by a JSON Pointer.
+// The parent of the target must exist; `-` appends to an array.
+func (obj Object) SetPointer(ptr string, v any) error {
+	p, err := ParsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if len(p) == 0 {
+		return fmt.Errorf("js: can not replace the object itself by pointer")
+	}
+	if obj == nil {
+		return p.fail(0, ErrNotObject)
+	}
+	_, err = p.set(obj, v, false)
+	return err
+}
+
+// RemovePointer removes the value referenced by a JSON Pointer.
+func (obj Object) RemovePointer(ptr string) error {
+	p, err := ParsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if obj == nil && len(p) > 0 {
+		return p.fail(0, ErrNotFound)
+	}
+	_, err = p.remove(obj)
+	return err
+}
@@ -0,0 +1,68 @@
+package js
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	p1, err1 := ParsePointer("/a~1b/c~0d/0")
+	p2, err2 := ParsePointer("")
+	_, err3 := ParsePointer("a/b")
+	_, err4 := ParsePointer("/a~2")
+
+	require(t, err1 == nil && len(p1) == 3 && p1[0] == "a/b" && p1[1] == "c~d" && p1[2] == "0")
+	require(t, p1.String() == "/a~1b/c~0d/0")
+	require(t, err2 == nil && len(p2) == 0 && p2.String() == "")
+	require(t, err3 != nil && err4 != nil)
+}
+
+func TestValue_Pointer(t *testing.T) {
+	v := MustParse([]byte(`{"foo":["bar","baz"],"":0,"a/b":1,"m~n":8,"k":{"x":null}}`))
+
+	foo, err1 := v.Pointer("/foo/1")
+	ab, _ := v.Pointer("/a~1b")
+	mn, _ := v.Pointer("/m~0n")
+	empty, _ := v.Pointer("/")
+	_, err2 := v.Pointer("/foo/2")
+	_, err3 := v.Pointer("/foo/01")
+	_, err4 := v.Pointer("/k/y")
+	root, _ := v.Pointer("")
+
+	var pe *PathError
+	require(t, err1 == nil && foo.String() == "baz")
+	require(t, ab.Int() == 1 && mn.Int() == 8 && empty.Int() == 0 && empty.IsNum())
+	require(t, errors.As(err2, &pe) && pe.Segment == "/foo/2" && errors.Is(err2, ErrNotFound))
+	require(t, err3 != nil)
+	require(t, errors.As(err4, &pe) && pe.Segment == "/k/y")
+	require(t, root.IsObject())
+}
+
+func TestObject_SetPointer(t *testing.T) {
+	obj := MustParseObject([]byte(`{"a":{"b":[1,2]}}`))
+
+	err1 := obj.SetPointer("/a/b/0", 10)
+	err2 := obj.SetPointer("/a/b/-", 3)
+	err3 := obj.SetPointer("/a/c", "x")
+	err4 := obj.SetPointer("/a/d/e", 1)
+	err5 := obj.SetPointer("/a/b/9", 1)
+
+	require(t, err1 == nil && err2 == nil && err3 == nil)
+	require(t, obj.String() == `{"a":{"b":[10,2,3],"c":"x"}}`)
+	require(t, errors.Is(err4, ErrNotFound))
+	require(t, errors.Is(err5, ErrNotFound))
+}
+
+func TestObject_RemovePointer(t *testing.T) {
+	obj := MustParseObject([]byte(`{"a":{"b":[1,2,3],"c":1}}`))
+
+	err1 := obj.RemovePointer("/a/b/1")
+	err2 := obj.RemovePointer("/a/c")
+	err3 := obj.RemovePointer("/a/c")
+	err4 := obj.RemovePointer("/a/b/-")
+
+	require(t, err1 == nil && err2 == nil)
+	require(t, obj.String() == `{"a":{"b":[1,3]}}`)
+	require(t, errors.Is(err3, ErrNotFound))
+	require(t, errors.Is(err4, ErrNotFound))
+}
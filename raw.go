@@ -0,0 +1,327 @@
+package js
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// RawValue is a JSON document kept as its original bytes and decoded lazily:
+// Get, Index and Path find the requested member by scanning the bytes without
+// decoding the rest of the document, and return it as a RawValue sharing
+// the same memory. Only the requested part is decoded by the accessors
+// (String, Int64, Value, etc.). The input is not validated beyond what the
+// scan touches; malformed parts are reported by LookupPath and Value.
+// The zero RawValue (and a missing member) represents null.
+type RawValue []byte
+
+// Value decodes the value (see Parse).
+func (r RawValue) Value() Value {
+	v, _ := Parse(r)
+	return v
+}
+
+// Decode decodes the value with options, reporting errors.
+func (r RawValue) Decode(opts ...ParseOptions) (Value, error) {
+	return Parse(r, opts...)
+}
+
+// Kind returns the kind of the value, judged by its first byte.
+func (r RawValue) Kind() Kind {
+	return rawKind(r)
+}
+
+// IsNull reports whether the value is null or missing.
+func (r RawValue) IsNull() bool {
+	return r.Kind() == KindNull
+}
+
+// Get returns the member of the object by key (null if missing).
+func (r RawValue) Get(key string) RawValue {
+	v, _ := r.lookup(pathSegment{key: key})
+	return v
+}
+
+// Index returns the element of the array by index; negative indexes
+// count from the end (null if missing).
+func (r RawValue) Index(i int) RawValue {
+	v, _ := r.lookup(pathSegment{index: i, isIndex: true})
+	return v
+}
+
+// Path returns the nested value by a path like `data.items[3].price`
+// (see Value.Path), or null if any segment is missing.
+func (r RawValue) Path(path string) RawValue {
+	v, _ := r.LookupPath(path)
+	return v
+}
+
+// LookupPath returns the nested value by path. A missing segment is reported
+// as a *PathError, malformed input as a *SyntaxError.
+func (r RawValue) LookupPath(path string) (RawValue, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	v := r
+	for i, s := range segs {
+		if v, err = v.lookup(s); err == ErrNotFound || err == ErrNotObject || err == ErrNotArray {
+			return nil, &PathError{path, formatPath(segs[:i+1]), err}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (r RawValue) lookup(s pathSegment) (RawValue, error) {
+	i := rawSpace(r, 0)
+	if i >= len(r) {
+		return nil, ErrNotFound
+	}
+	switch r[i] {
+	case '{':
+		if s.isIndex {
+			return nil, ErrNotArray
+		}
+		return r.member(i+1, s.key)
+	case '[':
+		idx, err := segmentIndex(s)
+		if err != nil {
+			return nil, err
+		}
+		return r.element(i+1, idx)
+	}
+	if s.isIndex {
+		return nil, ErrNotArray
+	}
+	return nil, ErrNotObject
+}
+
+// member scans the object members starting at i for key.
+func (r RawValue) member(i int, key string) (RawValue, error) {
+	for {
+		if i = rawSpace(r, i); i < len(r) && r[i] == '}' {
+			return nil, ErrNotFound
+		}
+		if i >= len(r) || r[i] != '"' {
+			return nil, r.syntaxError(i, "object key")
+		}
+		end := rawSkip(r, i)
+		if end < 0 {
+			return nil, r.syntaxError(i, "object key")
+		}
+		match := rawKeyEqual(r[i:end], key)
+		if i = rawSpace(r, end); i >= len(r) || r[i] != ':' {
+			return nil, r.syntaxError(i, "':' after object key")
+		}
+		start := rawSpace(r, i+1)
+		if end = rawSkip(r, start); end < 0 {
+			return nil, r.syntaxError(start, "value")
+		}
+		if match {
+			return r[start:end:end], nil
+		}
+		if i = rawSpace(r, end); i < len(r) && r[i] == ',' {
+			i++
+		} else if i >= len(r) || r[i] != '}' {
+			return nil, r.syntaxError(i, "',' or '}' after object member")
+		}
+	}
+}
+
+// element scans the array elements starting at i for the element idx.
+func (r RawValue) element(i, idx int) (RawValue, error) {
+	var elems []RawValue // only kept for negative indexes
+	for n := 0; ; n++ {
+		if i = rawSpace(r, i); i < len(r) && r[i] == ']' {
+			break
+		}
+		start := i
+		end := rawSkip(r, start)
+		if end < 0 {
+			return nil, r.syntaxError(start, "value")
+		}
+		if n == idx {
+			return r[start:end:end], nil
+		}
+		if idx < 0 {
+			elems = append(elems, r[start:end:end])
+		}
+		if i = rawSpace(r, end); i < len(r) && r[i] == ',' {
+			i++
+		} else if i >= len(r) || r[i] != ']' {
+			return nil, r.syntaxError(i, "',' or ']' after array element")
+		}
+	}
+	if idx < 0 && idx >= -len(elems) {
+		return elems[len(elems)+idx], nil
+	}
+	return nil, ErrNotFound
+}
+
+func (r RawValue) syntaxError(i int, expected string) error {
+	if i >= len(r) {
+		return newSyntaxError(r, int64(i), "unexpected end of JSON input")
+	}
+	return newSyntaxError(r, int64(i), "invalid character %s looking for %s", strconv.QuoteRune(rune(r[i])), expected)
+}
+
+// rawSpace returns the offset of the first non-whitespace byte at or after i.
+func rawSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// rawSkip returns the offset after the JSON value starting at i, or -1 if it
+// is malformed. Nested values are matched by brackets only, not validated.
+func rawSkip(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+	switch data[i] {
+	case '"':
+		for i++; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return -1
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			case '"':
+				end := rawSkip(data, i)
+				if end < 0 {
+					return -1
+				}
+				i = end - 1
+			}
+		}
+		return -1
+	}
+	start := i
+	for i < len(data) {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return i
+		}
+		i++
+	}
+	if i == start {
+		return -1
+	}
+	return i
+}
+
+// rawKeyEqual compares the quoted JSON string s with key.
+func rawKeyEqual(s []byte, key string) bool {
+	inner := s[1 : len(s)-1]
+	if bytes.IndexByte(inner, '\\') < 0 {
+		return string(inner) == key
+	}
+	var str string
+	return json.Unmarshal(s, &str) == nil && str == key
+}
+
+// scalar decodes a string, number, boolean or null without a full Parse;
+// other values are decoded by Parse. Malformed literals give nil.
+func (r RawValue) scalar() any {
+	s := bytes.TrimSpace(r)
+	if len(s) == 0 {
+		return nil
+	}
+	switch s[0] {
+	case '"':
+		if len(s) >= 2 && s[len(s)-1] == '"' {
+			if inner := s[1 : len(s)-1]; bytes.IndexByte(inner, '\\') < 0 {
+				return string(inner)
+			}
+		}
+		var str string
+		json.Unmarshal(s, &str) // ignore error
+		return str
+	case 't', 'f', 'n':
+		switch string(s) {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+		return nil // null or malformed
+	case '{', '[':
+		return r.Value().val
+	}
+	if !jsonNumberRx.Match(s) {
+		return nil
+	}
+	return json.Number(s)
+}
+
+// String returns the string value, or the JSON text of other values (empty for null).
+func (r RawValue) String() string {
+	switch v := r.scalar().(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	return string(bytes.TrimSpace(r))
+}
+
+// Int64 returns the value as int64 (see Value.Int64).
+func (r RawValue) Int64() int64 {
+	return Value{r.scalar()}.Int64()
+}
+
+// Int returns the value as int (see Value.Int).
+func (r RawValue) Int() int {
+	return int(r.Int64())
+}
+
+// Uint64 returns the value as uint64 (see Value.Uint64).
+func (r RawValue) Uint64() uint64 {
+	return Value{r.scalar()}.Uint64()
+}
+
+// Float64 returns the value as float64 (see Value.Float64).
+func (r RawValue) Float64() float64 {
+	return Value{r.scalar()}.Float64()
+}
+
+// Bool returns the value as bool (see Value.Bool).
+func (r RawValue) Bool() bool {
+	return Value{r.scalar()}.Bool()
+}
+
+// Time returns the value as time.Time (see Value.Time).
+func (r RawValue) Time() time.Time {
+	return Value{r.scalar()}.Time()
+}
+
+// MarshalJSON returns the raw bytes (null if empty).
+func (r RawValue) MarshalJSON() ([]byte, error) {
+	if len(r) == 0 {
+		return []byte("null"), nil
+	}
+	return r, nil
+}
+
+// UnmarshalJSON keeps a copy of data.
+func (r *RawValue) UnmarshalJSON(data []byte) error {
+	*r = append((*r)[:0], data...)
+	return nil
+}
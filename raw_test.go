@@ -0,0 +1,131 @@
+package js
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRawValue(t *testing.T) {
+	r := RawValue(`{
+		"symbol": "BTCUSDT",
+		"esc\"key": "aé\n",
+		"data": {"bids": [["100.5", 2], ["100.4", 3]], "ts": 1700000000123, "ok": true, "none": null},
+		"s": "x]}\"{["
+	}`)
+
+	require(t, r.Get("symbol").String() == "BTCUSDT")
+	require(t, r.Get(`esc"key`).String() == "aé\n")
+	require(t, r.Path("data.bids[1][0]").Float64() == 100.4)
+	require(t, r.Path("data.bids[-1][1]").Int() == 3)
+	require(t, r.Path("data.ts").Int64() == 1700000000123)
+	require(t, r.Path("data.ok").Bool())
+	require(t, r.Path("data.none").IsNull())
+	require(t, r.Path("data.bids[5]").IsNull())
+	require(t, r.Get("s").String() == `x]}"{[`)
+	require(t, r.Get("missing").IsNull() && r.Get("missing").String() == "")
+	require(t, r.Path("data.bids").Kind() == KindArray && r.Get("data").Kind() == KindObject)
+	require(t, r.Path("data.bids[0]").String() == `["100.5", 2]`)
+	require(t, r.Path("data.bids").Value().Array().Len() == 2)
+	require(t, r.Path("data.ts").Value().Int64() == 1700000000123)
+	require(t, r.Value().Path("data.ok").Bool())
+	require(t, r.Index(0).IsNull())
+
+	// malformed values give zero values
+	for _, bad := range []string{`"`, ` " `, `"abc`, `tx`, `f`, `nul`, `12abc`, `-`, `01`, `1.`, `x`} {
+		b := RawValue(bad)
+		require(t, b.String() == "" && b.Int64() == 0 && b.Float64() == 0 && !b.Bool() && b.Time().IsZero())
+		_, err := b.Decode()
+		require(t, err != nil)
+	}
+	require(t, RawValue(`{"a": tx}`).Get("a").Bool() == false && RawValue(` -1.5e2 `).Float64() == -150)
+
+	v, err := r.Get("data").Decode(ParseOptions{UseNumber: true})
+	require(t, err == nil && v.Path("ts").String() == "1700000000123")
+}
+
+func TestRawValue_LookupPath(t *testing.T) {
+	r := RawValue(`{"a":{"b":[1,2]},"c":1}`)
+
+	_, err := r.LookupPath("a.x")
+	var pe *PathError
+	require(t, errors.As(err, &pe) && pe.Segment == "a.x" && errors.Is(err, ErrNotFound))
+	_, err = r.LookupPath("c.d")
+	require(t, errors.Is(err, ErrNotObject))
+	_, err = r.LookupPath("a[0]")
+	require(t, errors.Is(err, ErrNotArray))
+
+	_, err = RawValue(`{"a":1 "b":2}`).LookupPath("b")
+	var se *SyntaxError
+	require(t, errors.As(err, &se) && se.Offset == 7)
+	_, err = RawValue(`{"a":[1,2`).LookupPath("a[5]")
+	require(t, errors.As(err, &se))
+
+	_, err = r.LookupPath("a.b[-9223372036854775808]")
+	require(t, errors.Is(err, ErrNotFound) && RawValue(`[1,2]`).Index(math.MinInt).IsNull())
+	require(t, RawValue(`[1,2]`).Index(-2).Int() == 1 && RawValue(`[1,2]`).Index(-3).IsNull())
+}
+
+func TestRawValue_JSON(t *testing.T) {
+	var s struct {
+		Raw RawValue `json:"raw"`
+	}
+	require(t, NewValue(Object{"raw": Object{"x": 1}}).MarshalTo(&s) == nil)
+	require(t, s.Raw.Get("x").Int() == 1)
+	require(t, Encode(s) == `{"raw":{"x":1}}`)
+	require(t, Encode(struct{ R RawValue }{}) == `{"R":null}`)
+	require(t, KindOf(s.Raw) == KindObject)
+}
+
+func benchmarkDocument() []byte {
+	var sb strings.Builder
+	sb.WriteString(`{"symbol":"BTCUSDT","bids":[`)
+	for i := 0; i < 2500; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(`["` + strconv.Itoa(60000-i) + `.50","0.` + strconv.Itoa(i) + `"]`)
+	}
+	sb.WriteString(`],"asks":[`)
+	for i := 0; i < 2500; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(`["` + strconv.Itoa(60001+i) + `.50","0.` + strconv.Itoa(i) + `"]`)
+	}
+	sb.WriteString(`],"lastUpdateId":1027024,"ts":1700000000123}`)
+	return []byte(sb.String())
+}
+
+func BenchmarkParseGet(b *testing.B) {
+	data := benchmarkDocument()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		obj, _ := ParseObject(data)
+		_ = obj.GetInt64("lastUpdateId")
+		_ = obj.GetInt64("ts")
+	}
+}
+
+func BenchmarkRawValueGet(b *testing.B) {
+	data := benchmarkDocument()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		r := RawValue(data)
+		_ = r.Get("lastUpdateId").Int64()
+		_ = r.Get("ts").Int64()
+	}
+}
+
+func BenchmarkRawValuePath(b *testing.B) {
+	data := benchmarkDocument()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = RawValue(data).Path("asks[0][0]").Float64()
+	}
+}
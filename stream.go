@@ -0,0 +1,154 @@
+package js
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// StreamArray iterates over the elements of the array at path in the JSON
+// document read from r, decoding one element at a time, so that huge documents
+// can be processed with memory bounded by the largest element.
+// The path has the syntax of Value.Path with an optional `$` root prefix,
+// e.g. `$.data.items` or `$` for a top-level array.
+//
+// The UseNumber and Ordered parse options are supported. The input after the
+// array is not read. Errors end the iteration; a missing path is reported as
+// a *PathError and malformed input as a *SyntaxError with the byte offset.
+func StreamArray(r io.Reader, path string, opts ...ParseOptions) iter.Seq2[Value, error] {
+	return func(yield func(Value, error) bool) {
+		o := parseOptions(opts)
+		if c, ok := r.(io.ReadCloser); ok && c != nil {
+			defer c.Close()
+		}
+		dec := json.NewDecoder(r)
+		if o.UseNumber {
+			dec.UseNumber()
+		}
+		err := streamSeek(dec, path)
+		for err == nil && dec.More() {
+			var v any
+			if o.Ordered {
+				v, err = decodeOrderedValue(dec)
+			} else {
+				err = dec.Decode(&v)
+			}
+			if err == nil && !yield(Value{v}, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(Value{}, streamError(dec, err))
+		}
+	}
+}
+
+// streamSeek reads dec up to the first element of the array at path.
+func streamSeek(dec *json.Decoder, path string) error {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	fail := func(i int, err error) error {
+		return &PathError{path, formatPath(segs[:i+1]), err}
+	}
+	for i, s := range segs {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			if s.isIndex {
+				return fail(i, ErrNotArray)
+			}
+			if err := streamSeekKey(dec, s.key); err != nil {
+				if err == ErrNotFound {
+					return fail(i, err)
+				}
+				return err
+			}
+		case json.Delim('['):
+			n, err := segmentIndex(s)
+			if err != nil || n < 0 {
+				return fail(i, ErrNotObject)
+			}
+			for ; n > 0 && dec.More(); n-- {
+				if err := skipValue(dec); err != nil {
+					return err
+				}
+			}
+			if !dec.More() {
+				return fail(i, ErrNotFound)
+			}
+		default:
+			if s.isIndex {
+				return fail(i, ErrNotArray)
+			}
+			return fail(i, ErrNotObject)
+		}
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return &PathError{path, path, ErrNotArray}
+	}
+	return nil
+}
+
+// streamSeekKey reads the object members of dec up to the value of key.
+func streamSeekKey(dec *json.Decoder, key string) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == key {
+			return nil
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return ErrNotFound
+}
+
+// skipValue reads the next value of dec token by token.
+func skipValue(dec *json.Decoder) error {
+	for depth := 0; ; {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func streamError(dec *json.Decoder, err error) error {
+	var se *json.SyntaxError
+	switch {
+	case errors.As(err, &se):
+		return &SyntaxError{Msg: se.Error(), Offset: se.Offset}
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		return &SyntaxError{Msg: "unexpected end of JSON input", Offset: dec.InputOffset()}
+	}
+	var pe *PathError
+	if errors.As(err, &pe) {
+		return err
+	}
+	return fmt.Errorf("js: StreamArray: %w", err)
+}
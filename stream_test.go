@@ -0,0 +1,100 @@
+package js
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStreamArray(t *testing.T) {
+	src := `{"meta":{"skip":[1,{"data":[0]}]},"data":{"items":[{"id":1},{"id":2},{"id":3}]},"tail":`
+
+	var ids []int
+	for v, err := range StreamArray(strings.NewReader(src), "$.data.items") {
+		require(t, err == nil)
+		ids = append(ids, v.Path("id").Int())
+	}
+	require(t, len(ids) == 3 && ids[2] == 3) // the malformed tail is not read
+
+	var n int
+	for v, err := range StreamArray(strings.NewReader(`[[1,2],[3,4,5]]`), "[1]") {
+		require(t, err == nil)
+		n += v.Int()
+	}
+	require(t, n == 12)
+
+	for v := range StreamArray(strings.NewReader(`[{"b":1,"a":12345678901234567890}]`), "$", ParseOptions{UseNumber: true, Ordered: true}) {
+		require(t, v.JSON() == `{"b":1,"a":12345678901234567890}`)
+	}
+
+	for range StreamArray(strings.NewReader(`[1,2,3]`), "") {
+		break // stopping early is allowed
+	}
+}
+
+func TestStreamArray_Errors(t *testing.T) {
+	streamErr := func(src, path string) (n int, err error) {
+		for _, e := range StreamArray(strings.NewReader(src), path) {
+			if e != nil {
+				err = e
+			} else {
+				n++
+			}
+		}
+		return
+	}
+
+	_, err := streamErr(`{"a":{"b":1}}`, "$.a.c")
+	var pe *PathError
+	require(t, errors.As(err, &pe) && pe.Segment == "a.c" && errors.Is(err, ErrNotFound))
+	_, err = streamErr(`{"a":{"b":1}}`, "$.a.b")
+	require(t, errors.Is(err, ErrNotArray))
+	_, err = streamErr(`{"a":[1]}`, "$.a[3]")
+	require(t, errors.Is(err, ErrNotFound))
+	_, err = streamErr(`[1]`, "$.a")
+	require(t, errors.Is(err, ErrNotObject))
+
+	n, err := streamErr(`{"a":[1,2,x]}`, "$.a")
+	var se *SyntaxError
+	require(t, n == 2 && errors.As(err, &se) && se.Offset > 0 && strings.Contains(err.Error(), "at offset"))
+	n, err = streamErr(`{"a":[1,2`, "$.a")
+	require(t, n == 2 && errors.As(err, &se))
+}
+
+// genReader generates `{"data":[{"i":0},{"i":1},...]}` without holding it in memory.
+type genReader struct {
+	n, i int
+	buf  []byte
+}
+
+func (g *genReader) Read(p []byte) (int, error) {
+	for len(g.buf) < len(p) && g.i <= g.n {
+		switch {
+		case g.i == 0:
+			g.buf = append(g.buf, `{"data":[`...)
+		case g.i == g.n:
+			g.buf = append(g.buf, `{"i":`+strconv.Itoa(g.i)+`}]}`...)
+		default:
+			g.buf = append(g.buf, `{"i":`+strconv.Itoa(g.i)+`},`...)
+		}
+		g.i++
+	}
+	if len(g.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, g.buf)
+	g.buf = g.buf[n:]
+	return n, nil
+}
+
+func TestStreamArray_Large(t *testing.T) {
+	var count, sum int
+	for v, err := range StreamArray(&genReader{n: 200_000}, "$.data") {
+		require(t, err == nil)
+		count++
+		sum += v.Path("i").Int()
+	}
+	require(t, count == 200_000 && sum == 200_000*200_001/2)
+}
@@ -0,0 +1,194 @@
+package js
+
+import (
+	"encoding/json"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SyncObject is an object that is safe for concurrent use, e.g. as shared
+// state updated by one goroutine and read by many.
+//
+// Readers work lock-free on the current immutable snapshot; writers are
+// serialized and replace the snapshot with an updated copy (copy-on-write).
+// Stored values are deep-copied on write and nested objects and arrays are
+// deep-copied on read, so they cannot be changed behind the object's back.
+// The zero value is an empty object ready to use.
+type SyncObject struct {
+	mu  sync.Mutex
+	cur atomic.Pointer[Object]
+}
+
+// NewSyncObject creates a SyncObject holding a deep copy of obj.
+func NewSyncObject(obj Object) *SyncObject {
+	s := &SyncObject{}
+	obj = deepCloneObject(obj)
+	s.cur.Store(&obj)
+	return s
+}
+
+func (s *SyncObject) load() Object {
+	if p := s.cur.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// write replaces the snapshot with fn applied to its shallow copy.
+func (s *SyncObject) write(fn func(obj Object) Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := fn(maps.Clone(s.load()))
+	s.cur.Store(&obj)
+}
+
+// Snapshot returns the current state as a read-only object without locking.
+func (s *SyncObject) Snapshot() FrozenObject {
+	return FrozenObject{s.load()}
+}
+
+// Object returns a mutable deep copy of the current state.
+func (s *SyncObject) Object() Object {
+	return deepCloneObject(s.load())
+}
+
+// Len returns the number of key-value pairs in the object.
+func (s *SyncObject) Len() int {
+	return s.load().Len()
+}
+
+// Has checks if the object contains the given key.
+func (s *SyncObject) Has(key string) bool {
+	return s.load().Has(key)
+}
+
+// Keys returns all keys of the object (in sorted order).
+func (s *SyncObject) Keys() []string {
+	return s.load().Keys()
+}
+
+// Get retrieves a copy of the value for the given key.
+func (s *SyncObject) Get(name string) Value {
+	return Value{deepClone(s.load().get(name))}
+}
+
+// GetBool retrieves the boolean value by key.
+func (s *SyncObject) GetBool(name string) bool {
+	return s.load().GetBool(name)
+}
+
+// GetStr retrieves the string value by key.
+func (s *SyncObject) GetStr(name string) string {
+	return s.load().GetStr(name)
+}
+
+// GetNum retrieves the numeric value (float64) by key.
+func (s *SyncObject) GetNum(name string) float64 {
+	return s.load().GetNum(name)
+}
+
+// GetInt retrieves the integer value (int) by key.
+func (s *SyncObject) GetInt(name string) int {
+	return s.load().GetInt(name)
+}
+
+// GetInt64 retrieves the 64-bit integer value (int64) by key.
+func (s *SyncObject) GetInt64(name string) int64 {
+	return s.load().GetInt64(name)
+}
+
+// GetUint64 retrieves the unsigned 64-bit integer value (uint64) by key.
+func (s *SyncObject) GetUint64(name string) uint64 {
+	return s.load().GetUint64(name)
+}
+
+// GetTime retrieves the time value (time.Time) by key.
+func (s *SyncObject) GetTime(name string) time.Time {
+	return s.load().GetTime(name)
+}
+
+// GetObj retrieves a copy of the nested object by key.
+func (s *SyncObject) GetObj(name string) Object {
+	return deepCloneObject(s.load().GetObj(name))
+}
+
+// GetArr retrieves a copy of the nested array by key.
+func (s *SyncObject) GetArr(name string) Array {
+	return deepCloneArray(s.load().GetArr(name))
+}
+
+// Set adds or updates a key-value pair (a deep copy of v) in the object.
+func (s *SyncObject) Set(name string, v any) *SyncObject {
+	v = deepClone(v)
+	s.write(func(obj Object) Object {
+		return obj.Set(name, v)
+	})
+	return s
+}
+
+// Delete removes the specified key-value pair from the object.
+func (s *SyncObject) Delete(name string) *SyncObject {
+	s.write(func(obj Object) Object {
+		return obj.Delete(name)
+	})
+	return s
+}
+
+// Extend merges the object with (deep copies of) other objects.
+func (s *SyncObject) Extend(o ...Object) *SyncObject {
+	clones := make([]Object, len(o))
+	for i, obj := range o {
+		clones[i] = deepCloneObject(obj)
+	}
+	s.write(func(obj Object) Object {
+		return obj.Extend(clones...)
+	})
+	return s
+}
+
+// Update atomically replaces the object with the result of fn.
+// fn receives a deep copy of the current state that it may modify and return;
+// it must not retain the object after returning. Concurrent writers wait for fn.
+func (s *SyncObject) Update(fn func(obj Object) Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := fn(deepCloneObject(s.load()))
+	s.cur.Store(&obj)
+}
+
+// CompareAndSwap sets the key to new if its current value equals old
+// (compared as JSON values; a missing key equals nil) and reports whether it did.
+func (s *SyncObject) CompareAndSwap(key string, old, new any) (swapped bool) {
+	new = deepClone(new)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.load()
+	if !deepEqual(cur.get(key), old) {
+		return false
+	}
+	obj := maps.Clone(cur).Set(key, new)
+	s.cur.Store(&obj)
+	return true
+}
+
+// String converts the object to a JSON string.
+func (s *SyncObject) String() string {
+	return s.load().String()
+}
+
+// MarshalJSON encodes the current state to JSON.
+func (s *SyncObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.load())
+}
+
+// UnmarshalJSON replaces the state with the decoded object.
+func (s *SyncObject) UnmarshalJSON(data []byte) error {
+	var obj Object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	s.write(func(Object) Object { return obj })
+	return nil
+}
@@ -0,0 +1,82 @@
+package js
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSyncObject(t *testing.T) {
+	var s SyncObject
+	require(t, s.Len() == 0 && s.String() == "null")
+
+	nested := Object{"port": 80}
+	s.Set("name", "x").Set("cfg", nested).Extend(Object{"a": 1})
+	nested["port"] = 81
+
+	require(t, s.GetStr("name") == "x")
+	require(t, s.GetObj("cfg").GetInt("port") == 80)
+	require(t, s.Keys()[0] == "a" && s.Has("cfg"))
+
+	s.GetObj("cfg").Set("port", 1)
+	s.Get("cfg").Object().Set("port", 1)
+	require(t, s.GetObj("cfg").GetInt("port") == 80)
+
+	snap := s.Snapshot()
+	s.Delete("name")
+	require(t, snap.GetStr("name") == "x" && !s.Has("name"))
+
+	require(t, s.CompareAndSwap("a", 1.0, 2))
+	require(t, !s.CompareAndSwap("a", 1, 3))
+	require(t, s.CompareAndSwap("missing", nil, "new"))
+	require(t, s.GetInt("a") == 2 && s.GetStr("missing") == "new")
+
+	s.Update(func(obj Object) Object {
+		obj.GetObj("cfg").Set("port", 443)
+		return obj
+	})
+	require(t, s.GetObj("cfg").GetInt("port") == 443)
+	require(t, snap.GetObj("cfg").GetInt("port") == 80)
+
+	require(t, s.UnmarshalJSON([]byte(`{"b":true}`)) == nil)
+	require(t, NewValue(&s).JSON() == `{"b":true}`)
+	require(t, NewSyncObject(Object{"c": 1}).Object().GetInt("c") == 1)
+}
+
+func TestSyncObject_Concurrent(t *testing.T) {
+	s := NewSyncObject(Object{"n": 0, "cas": 0, "ticker": Object{"price": 0}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Update(func(obj Object) Object {
+					obj.Set("n", obj.GetInt("n")+1)
+					obj.GetObj("ticker").Set("price", j)
+					return obj
+				})
+				s.Set("k"+strconv.Itoa(j%5), j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				snap := s.Snapshot()
+				_ = snap.GetObj("ticker").GetNum("price")
+				_ = s.GetObj("ticker").String()
+				_ = s.String()
+				for {
+					c := s.Get("cas")
+					if s.CompareAndSwap("cas", c.val, c.Int()+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require(t, s.GetInt("n") == 800)
+	require(t, s.GetInt("cas") == 800)
+}
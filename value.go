@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -22,21 +21,21 @@ func NewValue(v any) Value {
 	return Value{v}
 }
 
-func Parse(data []byte) (v Value, err error) {
+func Parse(data []byte, opts ...ParseOptions) (v Value, err error) {
 	if len(data) == 0 {
 		return
 	}
-	err = json.Unmarshal(bytes.TrimSpace(data), &v.val)
+	err = unmarshal(bytes.TrimSpace(data), &v.val, parseOptions(opts))
 	return
 }
 
-func MustParse(data []byte) Value {
-	return must(Parse(data))
+func MustParse(data []byte, opts ...ParseOptions) Value {
+	return must(Parse(data, opts...))
 }
 
-func ReadValue(r io.Reader) (v Value, err error) {
+func ReadValue(r io.Reader, opts ...ParseOptions) (v Value, err error) {
 	defer catch(&err)
-	return Parse(readAll(r))
+	return Parse(readInput(r, parseOptions(opts)), opts...)
 }
 
 func (v Value) Value() any {
@@ -48,11 +47,17 @@ func (v Value) MarshalJSON() ([]byte, error) {
 }
 
 func (v *Value) UnmarshalJSON(data []byte) error {
-	return json.Unmarshal(data, &v.val)
+	val, err := decodeJSON(data, false)
+	if err == nil {
+		v.val = val
+	}
+	return err
 }
 
+// MarshalTo stores the value into val (a pointer to a struct, map, slice etc.)
+// as json.Unmarshal would store its JSON, without encoding it.
 func (v Value) MarshalTo(val any) error {
-	return json.Unmarshal(v.Bytes(), val)
+	return decodeInto(v.val, val)
 }
 
 func (v Value) Bytes() (data []byte) {
@@ -75,6 +80,9 @@ func (v Value) Empty() bool {
 		return v == ""
 	case int, int32, int64, uint, uint32, uint64, float32, float64:
 		return v == 0
+	case json.Number:
+		f, _ := v.Float64()
+		return f == 0
 	case map[string]any:
 		return len(v) == 0
 	case []any:
@@ -102,12 +110,11 @@ func (v Value) IsNum() bool {
 }
 
 func (v Value) IsObject() bool {
-	return v.Object() != nil
+	return v.Kind() == KindObject
 }
 
 func (v Value) IsArray() bool {
-	kind := reflect.ValueOf(v.val).Kind()
-	return kind == reflect.Slice || kind == reflect.Array
+	return v.Kind() == KindArray
 }
 
 func (v Value) Array() Array {
@@ -137,6 +144,8 @@ func (v Value) Bool() bool {
 	return v.Int64() != 0
 }
 
+// String returns the value as a string: strings as is, numbers in decimal
+// notation, time.Time in RFC 3339 as in its JSON encoding, objects and arrays as JSON.
 func (v Value) String() string {
 	if isNil(v.val) {
 		return ""
@@ -144,6 +153,8 @@ func (v Value) String() string {
 	switch val := v.val.(type) {
 	case string:
 		return val
+	case json.Number:
+		return string(val)
 	case []byte:
 		return string(val)
 	case int:
@@ -162,6 +173,8 @@ func (v Value) String() string {
 		return strconv.FormatFloat(float64(val), 'f', -1, 64)
 	case float32:
 		return strconv.FormatFloat(float64(val), 'f', -1, 64)
+	case time.Time:
+		return val.Format(time.RFC3339Nano) // as in JSON
 	case fmt.Stringer:
 		return val.String()
 	case error:
@@ -178,6 +191,20 @@ func (v Value) Int() int {
 }
 
 func (v Value) Uint64() uint64 {
+	switch val := v.val.(type) {
+	case uint64:
+		return val
+	case uint:
+		return uint64(val)
+	case json.Number:
+		if n, err := strconv.ParseUint(string(val), 10, 64); err == nil {
+			return n
+		}
+	case string:
+		if n, err := strconv.ParseUint(val, 0, 64); err == nil {
+			return n
+		}
+	}
 	return uint64(v.Int64())
 }
 
@@ -208,6 +235,12 @@ func (v Value) Int64() (num int64) {
 		}
 	case []byte:
 		return NewValue(string(val)).Int64()
+	case json.Number:
+		if num, err := val.Int64(); err == nil {
+			return num
+		}
+		f, _ := val.Float64()
+		return int64(f)
 	case string:
 		if strings.IndexByte(val, '.') >= 0 {
 			f, _ := strconv.ParseFloat(val, 64)
@@ -249,6 +282,8 @@ func (v Value) Float64() (num float64) {
 		return float64(val)
 	case []byte:
 		num, _ = strconv.ParseFloat(string(val), 64)
+	case json.Number:
+		num, _ = strconv.ParseFloat(string(val), 64)
 	case string:
 		num, _ = strconv.ParseFloat(val, 64)
 	default:
@@ -258,7 +293,10 @@ func (v Value) Float64() (num float64) {
 }
 
 func (v Value) Time() time.Time {
-	if IsNum(v) {
+	if t, ok := v.val.(time.Time); ok {
+		return t
+	}
+	if IsNum(v.val) {
 		return time.Unix(NewValue(v).Int64(), 0)
 	}
 	t, _ := ParseTime(ToStr(v))
@@ -271,16 +309,18 @@ func isNil(v any) bool {
 
 func IsNum(v any) bool {
 	switch v.(type) {
-	case int, uint, int8, uint8, int16, uint16, int32, uint32, int64, uint64, float32, float64:
+	case int, uint, int8, uint8, int16, uint16, int32, uint32, int64, uint64, float32, float64, json.Number:
 		return true
 	}
 	return false
 }
 
 func IsInt(v any) bool {
-	switch v.(type) {
+	switch v := v.(type) {
 	case int, uint, int8, uint8, int16, uint16, int32, uint32, int64, uint64:
 		return true
+	case json.Number:
+		return !strings.ContainsAny(string(v), ".eE")
 	}
 	return false
 }
@@ -325,6 +365,12 @@ func And(v ...any) bool {
 
 func Cmp(a, b any) int {
 	if IsNum(a) && IsNum(b) {
+		if isFloatExact(a) && isFloatExact(b) {
+			return _cmp(ToNum(a), ToNum(b))
+		}
+		if ra, rb := toRat(a), toRat(b); ra != nil && rb != nil {
+			return ra.Cmp(rb)
+		}
 		return _cmp(ToNum(a), ToNum(b))
 	}
 	return _cmp(ToStr(a), ToStr(b))
@@ -339,6 +385,115 @@ func _cmp[T int64 | float64 | string](a, b T) int {
 	return 0
 }
 
+// typeName returns the JSON type name of v:
+// null, boolean, number, string, array or object.
+func typeName(v any) string {
+	return jsonKind(v).String()
+}
+
+// jsonKind is KindOf with binary and time values reported as strings,
+// as they are encoded.
+func jsonKind(v any) Kind {
+	switch k := KindOf(v); k {
+	case KindBinary, KindTime:
+		return KindString
+	default:
+		return k
+	}
+}
+
+// deepEqual reports whether a and b are equal JSON values of the same type:
+// numbers are compared by Cmp, objects and arrays member by member.
+func deepEqual(a, b any) bool {
+	switch {
+	case isNil(a) || isNil(b):
+		return isNil(a) && isNil(b)
+	case IsNum(a) || IsNum(b):
+		return IsNum(a) && IsNum(b) && Cmp(a, b) == 0
+	}
+	switch a := a.(type) {
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	}
+	if aa, ok := asArray(a); ok {
+		bb, ok := asArray(b)
+		if !ok || len(aa) != len(bb) {
+			return false
+		}
+		for i := range aa {
+			if !deepEqual(aa[i], bb[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if oa, ok := asObject(a); ok {
+		ob, ok := asObject(b)
+		if !ok || len(oa) != len(ob) {
+			return false
+		}
+		for k, v := range oa {
+			if w, ok := ob[k]; !ok || !deepEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return Cmp(a, b) == 0
+}
+
+// DeepClone returns a copy of the value in which all nested objects and arrays
+// are copied too. Values of other Go types (structs, typed slices) are shared.
+func (v Value) DeepClone() Value {
+	return Value{deepClone(v.val)}
+}
+
+// deepClone returns a copy of v in which all nested objects and arrays
+// are copied too; other values are shared.
+func deepClone(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return map[string]any(deepCloneObject(val))
+	case Object:
+		return deepCloneObject(val)
+	case []any:
+		return []any(deepCloneArray(val))
+	case Array:
+		return deepCloneArray(val)
+	case *OrderedObject:
+		return val.Clone()
+	case Value:
+		return Value{deepClone(val.val)}
+	}
+	return v
+}
+
+func deepCloneObject(obj Object) Object {
+	if obj == nil {
+		return nil
+	}
+	res := make(Object, len(obj))
+	for k, v := range obj {
+		res[k] = deepClone(v)
+	}
+	return res
+}
+
+func deepCloneArray(arr Array) Array {
+	if arr == nil {
+		return nil
+	}
+	res := make(Array, len(arr))
+	for i, v := range arr {
+		res[i] = deepClone(v)
+	}
+	return res
+}
+
 func ParseTime(s string) (time.Time, error) {
 	s = strings.TrimSpace(s)
 	switch {